@@ -0,0 +1,22 @@
+package scold
+
+// Checker decides whether the executable's output is a correct answer to the
+// test. It is used by TestingBatch in place of the lexeme-wise comparison to
+// accommodate problems that have several correct answers (special judges).
+//
+// Check receives the test's input, the expected answer and the result of the
+// execution, and returns either OK or WA accompanied by an optional message
+// explaining the decision. If the checker itself fails, it should return an
+// error, in which case the test is assigned IE verdict.
+type Checker interface {
+	Check(input, answer string, out ExecutionResult) (Verdict, string, error)
+}
+
+// CheckerFunc represents an implementation of Checker that is a plain Go
+// function.
+type CheckerFunc func(input, answer string, out ExecutionResult) (Verdict, string, error)
+
+// Check will call the underlying Go function to judge the output.
+func (f CheckerFunc) Check(input, answer string, out ExecutionResult) (Verdict, string, error) {
+	return f(input, answer, out)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kuredoro/scold"
+)
+
+// Exit codes of testlib-compatible checkers.
+const (
+	testlibOK            = 0
+	testlibWA            = 1
+	testlibPE            = 2
+	testlibFail          = 3
+	testlibDirt          = 4
+	testlibPoints        = 7
+	testlibUnexpectedEOF = 8
+)
+
+// ExternalChecker runs a testlib-style checker executable. The checker is
+// invoked as `checker <input> <output> <answer>`, where the arguments are
+// paths to the temporary files holding the test's input, the program's
+// output and the expected answer respectively. The verdict is derived from
+// the exit code and the checker's comment is what it printed.
+type ExternalChecker struct {
+	// Path is the path to the checker executable.
+	Path string
+}
+
+// Check writes the input, the output and the answer to the temporary files
+// and runs the checker on them. The checker's failure and the exit codes
+// unknown to testlib are reported as an error.
+func (c *ExternalChecker) Check(input, answer string, out scold.ExecutionResult) (scold.Verdict, string, error) {
+	dir, err := os.MkdirTemp("", "scold-checker-")
+	if err != nil {
+		return scold.IE, "", err
+	}
+	defer os.RemoveAll(dir)
+
+	paths := make([]string, 3)
+	for i, file := range []struct{ name, text string }{
+		{"input.txt", input},
+		{"output.txt", out.Stdout},
+		{"answer.txt", answer},
+	} {
+		paths[i] = filepath.Join(dir, file.name)
+
+		err = os.WriteFile(paths[i], []byte(file.text), 0o644)
+		if err != nil {
+			return scold.IE, "", err
+		}
+	}
+
+	var comment bytes.Buffer
+	cmd := exec.Command(c.Path, paths...)
+	cmd.Stdout = &comment
+	cmd.Stderr = &comment
+
+	err = cmd.Run()
+	msg := strings.TrimSpace(comment.String())
+
+	exitCode := testlibOK
+	if ee, ok := err.(*exec.ExitError); ok {
+		exitCode = ee.ExitCode()
+	} else if err != nil {
+		return scold.IE, "", err
+	}
+
+	return testlibVerdict(exitCode, msg)
+}
+
+// testlibVerdict maps the exit code of a testlib-style checker to the
+// verdict.
+func testlibVerdict(exitCode int, msg string) (scold.Verdict, string, error) {
+	switch exitCode {
+	case testlibOK, testlibPoints:
+		return scold.OK, msg, nil
+	case testlibWA, testlibPE, testlibDirt, testlibUnexpectedEOF:
+		return scold.WA, msg, nil
+	case testlibFail:
+		return scold.IE, "", fmt.Errorf("failed: %s", msg)
+	}
+
+	return scold.IE, "", fmt.Errorf("unexpected exit code %d: %s", exitCode, msg)
+}
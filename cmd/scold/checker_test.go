@@ -0,0 +1,109 @@
+//go:build !windows
+
+package main
+
+import (
+	"testing"
+
+	"github.com/kuredoro/scold"
+)
+
+// The checker accepts the output equal to the answer and fails on the
+// input "fail".
+const equalityChecker = `#!/bin/sh
+read input < "$1"
+read output < "$2"
+read answer < "$3"
+if [ "$input" = "fail" ]; then
+	echo "broken test"
+	exit 3
+fi
+if [ "$output" != "$answer" ]; then
+	echo "expected $answer, got $output"
+	exit 1
+fi
+echo "ok"
+`
+
+func TestTestlibVerdict(t *testing.T) {
+	cases := []struct {
+		name     string
+		exitCode int
+		verdict  scold.Verdict
+		msg      string
+		wantErr  bool
+	}{
+		{"ok", testlibOK, scold.OK, "msg", false},
+		{"points", testlibPoints, scold.OK, "msg", false},
+		{"wrong answer", testlibWA, scold.WA, "msg", false},
+		{"presentation error", testlibPE, scold.WA, "msg", false},
+		{"dirt", testlibDirt, scold.WA, "msg", false},
+		{"unexpected eof", testlibUnexpectedEOF, scold.WA, "msg", false},
+		{"fail", testlibFail, scold.IE, "", true},
+		{"unknown code", 42, scold.IE, "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			verdict, msg, err := testlibVerdict(c.exitCode, "msg")
+
+			if verdict != c.verdict {
+				t.Errorf("got verdict %v, want %v", verdict, c.verdict)
+			}
+
+			if msg != c.msg {
+				t.Errorf("got message %q, want %q", msg, c.msg)
+			}
+
+			if (err != nil) != c.wantErr {
+				t.Errorf("got error %v, want error: %v", err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestExternalChecker(t *testing.T) {
+	checker := &ExternalChecker{Path: writeScript(t, "checker.sh", equalityChecker)}
+
+	cases := []struct {
+		name    string
+		input   string
+		answer  string
+		output  string
+		verdict scold.Verdict
+		msg     string
+		wantErr bool
+	}{
+		{"accepted", "1\n", "2\n", "2\n", scold.OK, "ok", false},
+		{"rejected", "1\n", "2\n", "3\n", scold.WA, "expected 2, got 3", false},
+		{"checker failed", "fail\n", "2\n", "2\n", scold.IE, "", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			verdict, msg, err := checker.Check(c.input, c.answer, scold.ExecutionResult{Stdout: c.output})
+
+			if verdict != c.verdict {
+				t.Errorf("got verdict %v, want %v", verdict, c.verdict)
+			}
+
+			if msg != c.msg {
+				t.Errorf("got message %q, want %q", msg, c.msg)
+			}
+
+			if (err != nil) != c.wantErr {
+				t.Errorf("got error %v, want error: %v", err, c.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing checker is an error", func(t *testing.T) {
+		checker := &ExternalChecker{Path: "/nonexistent/checker"}
+
+		verdict, _, err := checker.Check("1\n", "1\n", scold.ExecutionResult{Stdout: "1\n"})
+
+		if verdict != scold.IE || err == nil {
+			t.Errorf("got verdict %v and error %v, want IE with an error", verdict, err)
+		}
+	})
+}
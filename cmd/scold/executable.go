@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"sync"
+	"time"
 
 	"github.com/kuredoro/scold"
 )
@@ -12,10 +15,90 @@ import (
 type Executable struct {
 	Path string
 	Args []string
+
+	// MemoryLimit is the maximum resident set size the process is allowed
+	// to have. Zero means no limit.
+	MemoryLimit scold.ByteSize
+
+	// CPULimit is the maximum CPU time the process is allowed to consume.
+	// Zero means no limit.
+	CPULimit time.Duration
+
+	// TlMode tells which time the time limit restricts. In the CPU time
+	// mode, the tests that override the time limit override CPULimit.
+	TlMode scold.TimeLimitMode
+
+	// OutputLimit is the maximum combined size of stdout and stderr the
+	// process is allowed to print. Zero means no limit.
+	OutputLimit scold.ByteSize
+}
+
+// command prepares the executable to be run with the extra arguments
+// appended to the ones specified in Args.
+func (e *Executable) command(extraArgs ...string) *exec.Cmd {
+	args := make([]string, 0, len(e.Args)+len(extraArgs))
+	args = append(args, e.Args...)
+	args = append(args, extraArgs...)
+
+	cmd := exec.Command(e.Path, args...)
+
+	// The executable may be a wrapper, like a shell script, that runs the
+	// actual solution as its child. Putting them in a separate process
+	// group allows to kill them all at once.
+	setProcessGroup(cmd)
+
+	return cmd
+}
+
+// withTestLimits returns the executable with the limits adjusted for the
+// test that is run with ctx. In the CPU time mode, the test may override
+// the CPU time limit, even if the suite has none.
+func (e *Executable) withTestLimits(ctx context.Context) *Executable {
+	tl, ok := scold.TimeLimitFromContext(ctx)
+	if !ok || e.TlMode != scold.CPUTimeMode {
+		return e
+	}
+
+	adjusted := *e
+	adjusted.CPULimit = tl
+	return &adjusted
+}
+
+// guardLimits starts watching the resource usage of the process, if any
+// limits are set. The returned function stops the watch and returns
+// MLError or TLError if the process was killed for exceeding a limit.
+func (e *Executable) guardLimits(proc *os.Process) (stop func() error) {
+	if e.MemoryLimit == 0 && e.CPULimit == 0 {
+		return func() error { return nil }
+	}
+
+	stopCh := make(chan struct{})
+	exceeded := watchResources(proc, e.MemoryLimit, e.CPULimit, stopCh)
+
+	return func() error {
+		close(stopCh)
+		return <-exceeded
+	}
+}
+
+// checkLimits returns MLError or TLError if the exited process turned out
+// to consume more resources than allowed.
+func (e *Executable) checkLimits(out scold.ExecutionResult) error {
+	if e.MemoryLimit != 0 && out.PeakMemory > e.MemoryLimit {
+		return scold.MLError
+	}
+
+	if e.CPULimit != 0 && out.CPUTime() > e.CPULimit {
+		return scold.TLError
+	}
+
+	return nil
 }
 
 func (e *Executable) Run(ctx context.Context, r io.Reader) (scold.ExecutionResult, error) {
-	cmd := exec.Command(e.Path, e.Args...)
+	e = e.withTestLimits(ctx)
+
+	cmd := e.command()
 	cmd.Stdin = r
 
 	stdoutPipe, err := cmd.StdoutPipe()
@@ -33,39 +116,65 @@ func (e *Executable) Run(ctx context.Context, r io.Reader) (scold.ExecutionResul
 		return scold.ExecutionResult{}, fmt.Errorf("executable: %v", err)
 	}
 
+	stopGuard := e.guardLimits(cmd.Process)
+
 	stdout := make([]byte, 0, 1024)
 	stderr := make([]byte, 0, 1024)
 	stdoutComplete := make(chan error)
 	stderrComplete := make(chan error)
 
-	go listenPipe(stdoutPipe, &stdout, stdoutComplete)
-	go listenPipe(stderrPipe, &stderr, stderrComplete)
+	budget := &outputBudget{limit: e.OutputLimit}
+	go pipeListener(stdoutPipe, &stdout, budget, stdoutComplete)
+	go pipeListener(stderrPipe, &stderr, budget, stderrComplete)
 
+	// Once the process is started, every exit has to go through the
+	// cleanup below, so that the process wouldn't be left running and
+	// the goroutines wouldn't leak. Hence, the errors are only recorded.
+	var runErr error
+	outputExceeded, killed := false, false
+	cancelled := ctx.Done()
 	for doneCount := 0; doneCount != 2; {
 		select {
-		case <-ctx.Done():
-			// When process is killed the pipes are closed. the listenPipes
-			// will receive EOF and return nil.
-			err = cmd.Process.Kill()
-			if err != nil {
-				return scold.ExecutionResult{}, fmt.Errorf("executable: kill: %v", err)
-			}
+		case <-cancelled:
+			cancelled = nil
 		case err := <-stdoutComplete:
-			if err != nil {
-				return scold.ExecutionResult{}, fmt.Errorf("executable: stdout: %v", err)
+			outputExceeded = outputExceeded || err == scold.OLError
+			if err != nil && err != scold.OLError && runErr == nil {
+				runErr = fmt.Errorf("executable: stdout: %v", err)
 			}
 			doneCount++
 		case err := <-stderrComplete:
-			if err != nil {
-				return scold.ExecutionResult{}, fmt.Errorf("executable: stderr: %v", err)
+			outputExceeded = outputExceeded || err == scold.OLError
+			if err != nil && err != scold.OLError && runErr == nil {
+				runErr = fmt.Errorf("executable: stderr: %v", err)
 			}
 			doneCount++
 		}
+
+		// When the process group is killed the pipes are closed. the
+		// listenPipes will receive EOF and return nil. If the output limit
+		// is exceeded or a pipe is broken, the process would block on
+		// writing to the abandoned pipe, so it has to be killed too.
+		if (ctx.Err() != nil || outputExceeded || runErr != nil) && !killed {
+			err = killProcessGroup(cmd.Process)
+			if err != nil {
+				// At least the process itself should not outlive the run.
+				_ = cmd.Process.Kill()
+				if runErr == nil {
+					runErr = fmt.Errorf("executable: kill: %v", err)
+				}
+			}
+			killed = true
+		}
 	}
 
 	close(stdoutComplete)
 	close(stderrComplete)
 
+	// The process has closed its pipes, so it's either exited or is about
+	// to, so the resource usage won't grow much from now on.
+	limitErr := stopGuard()
+
 	out := scold.ExecutionResult{
 		ExitCode: 0,
 		Stdout:   string(stdout),
@@ -74,23 +183,71 @@ func (e *Executable) Run(ctx context.Context, r io.Reader) (scold.ExecutionResul
 
 	err = cmd.Wait()
 
+	if runErr != nil {
+		return scold.ExecutionResult{}, runErr
+	}
+
 	if ee, ok := err.(*exec.ExitError); ok {
 		out.ExitCode = ee.ExitCode()
-		return out, nil
+	} else if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("executable: %v", err)
 	}
 
-	if err != nil {
-		return scold.ExecutionResult{}, fmt.Errorf("executable: %v", err)
+	recordUsage(&out, cmd.ProcessState)
+
+	if outputExceeded {
+		return out, scold.OLError
+	}
+
+	if limitErr == nil {
+		limitErr = e.checkLimits(out)
+	}
+
+	return out, limitErr
+}
+
+// outputBudget keeps track of the output read from the pipes of a process,
+// so that it wouldn't exceed the limit. Zero limit means no limit.
+type outputBudget struct {
+	limit scold.ByteSize
+
+	mu   sync.Mutex
+	used scold.ByteSize
+}
+
+// spend requests n more bytes of output and returns how many of them fit
+// into the limit.
+func (b *outputBudget) spend(n int) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	allowed := scold.ByteSize(n)
+	if b.limit != 0 && b.used+allowed > b.limit {
+		allowed = b.limit - b.used
 	}
 
-	return out, nil
+	b.used += allowed
+	return int(allowed)
 }
 
-func listenPipe(pipe io.Reader, out *[]byte, done chan error) {
+// pipeListener reads the output of the process. It's a variable, so that
+// the tests could break the pipes.
+var pipeListener = listenPipe
+
+// listenPipe reads the pipe until EOF into out. If the budget runs out,
+// OLError is reported, and the rest of the output is left unread.
+func listenPipe(pipe io.Reader, out *[]byte, budget *outputBudget, done chan error) {
 	buf := make([]byte, 1024)
 	for {
 		n, err := pipe.Read(buf)
-		*out = append(*out, buf[:n]...)
+
+		allowed := budget.spend(n)
+		*out = append(*out, buf[:allowed]...)
+
+		if allowed != n {
+			done <- scold.OLError
+			return
+		}
 
 		if err != nil && err != io.EOF {
 			done <- err
@@ -0,0 +1,154 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kuredoro/scold"
+)
+
+func TestExecutableKillsProcessTree(t *testing.T) {
+	// The child inherits the pipes, so if it survives the kill, Run won't
+	// see EOF until it exits.
+	script := filepath.Join(t.TempDir(), "wrapper.sh")
+	err := os.WriteFile(script, []byte("#!/bin/sh\nsleep 30 &\nwait\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exe := &Executable{Path: script}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = exe.Run(ctx, strings.NewReader(""))
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("got error %v, want none", err)
+	}
+
+	if elapsed > 5*time.Second {
+		t.Errorf("Run returned after %v, want it to return promptly after the cancellation", elapsed)
+	}
+}
+
+func TestExecutableOutputLimit(t *testing.T) {
+	exe := &Executable{
+		Path:        "/bin/sh",
+		Args:        []string{"-c", "while :; do echo spam; echo spam >&2; done"},
+		OutputLimit: 64 * scold.Kilobyte,
+	}
+
+	out, err := exe.Run(context.Background(), strings.NewReader(""))
+
+	if err != scold.OLError {
+		t.Fatalf("got error %v, want %v", err, scold.OLError)
+	}
+
+	if size := len(out.Stdout) + len(out.Stderr); size != int(exe.OutputLimit) {
+		t.Errorf("got %d bytes of output, want exactly the limit of %d bytes", size, exe.OutputLimit)
+	}
+}
+
+func TestExecutableBrokenPipe(t *testing.T) {
+	// The pipe breaks once the process has saved its PID, so that it's
+	// known which process must not outlive Run.
+	pipeListener = func(pipe io.Reader, out *[]byte, budget *outputBudget, done chan error) {
+		_, err := bufio.NewReader(pipe).ReadString('\n')
+		if err == io.EOF {
+			done <- nil
+			return
+		}
+
+		done <- errors.New("broken pipe")
+	}
+	defer func() { pipeListener = listenPipe }()
+
+	pidFile := filepath.Join(t.TempDir(), "pid")
+
+	// The limits make Run watch the process, which has to be stopped too.
+	exe := &Executable{
+		Path:        "/bin/sh",
+		Args:        []string{"-c", "echo $$ > \"$0\"; echo ready; sleep 30", pidFile},
+		MemoryLimit: scold.Gigabyte,
+		CPULimit:    time.Minute,
+	}
+
+	start := time.Now()
+	_, err := exe.Run(context.Background(), strings.NewReader(""))
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("got no error, want the pipe's one")
+	}
+
+	if elapsed > 5*time.Second {
+		t.Errorf("Run returned after %v, want it to return promptly after the pipe broke", elapsed)
+	}
+
+	text, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(text)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Even a zombie can be signalled, so the process must be reaped too.
+	if err := syscall.Kill(pid, 0); err != syscall.ESRCH {
+		t.Errorf("process %d still exists after Run (kill error %v)", pid, err)
+	}
+}
+
+// helperEnv is the environment variable that makes TestHelperProcess
+// behave as the process needed by a test.
+const helperEnv = "SCOLD_TEST_HELPER"
+
+// TestHelperProcess isn't a real test. It's run by the other tests as
+// a process that allocates memory or burns CPU until it's killed.
+func TestHelperProcess(t *testing.T) {
+	switch os.Getenv(helperEnv) {
+	case "allocate":
+		// Every page must be touched to become resident.
+		mem := make([]byte, 256*scold.Megabyte)
+		for i := 0; i < len(mem); i += 4096 {
+			mem[i] = 1
+		}
+
+		time.Sleep(30 * time.Second)
+	case "spin":
+		for start := time.Now(); time.Since(start) < 30*time.Second; {
+		}
+	default:
+		return
+	}
+
+	os.Exit(0)
+}
+
+// helperExecutable runs TestHelperProcess as a child of a shell, so that
+// the limits have to be enforced on the descendants of the executable.
+func helperExecutable(t *testing.T, behavior string) *Executable {
+	t.Helper()
+	t.Setenv(helperEnv, behavior)
+
+	return &Executable{
+		Path: "/bin/sh",
+		Args: []string{"-c", `"$0" -test.run='^TestHelperProcess$'; exit $?`, os.Args[0]},
+	}
+}
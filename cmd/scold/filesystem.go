@@ -63,7 +63,14 @@ func findFile(userPath string) (string, error) {
 	return "", fmt.Errorf("%s does not exist", pathForError)
 }
 
-func readInputs(inputsPath string) (scold.Inputs, []error) {
+// readInputs loads the tests either from the inputs file or from the
+// directory with a file per each input and answer.
+func readInputs(inputsPath string, patterns []scold.TestFilePattern) (scold.Inputs, []error) {
+	stat, err := os.Stat(inputsPath)
+	if err == nil && stat.IsDir() {
+		return scold.LoadInputsDir(os.DirFS(inputsPath), patterns)
+	}
+
 	inputsFile, err := os.Open(inputsPath)
 	if err != nil {
 		return scold.Inputs{}, []error{fmt.Errorf("open scold inputs file: %w", err)}
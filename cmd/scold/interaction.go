@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kuredoro/scold"
+)
+
+// Prefixes that mark the direction of the lines in the transcript.
+const (
+	solutionPrefix   = "> "
+	interactorPrefix = "< "
+)
+
+// Interaction runs the solution together with an interactor, so that the
+// standard output of one is the standard input of the other. Following the
+// testlib convention, the interactor is invoked as
+// `interactor <input-file> <output-file>`, where the input file contains the
+// test's input and the output file is where the interactor may leave its
+// own output.
+//
+// The solution's limits apply as when it's run alone. The output limit
+// restricts the combined size of the solution's stdout and stderr, and the
+// size of the transcript.
+type Interaction struct {
+	Solution   *Executable
+	Interactor *Executable
+}
+
+// Run runs the interaction on the test's input read from r. The result is
+// the solution's, with the interactor's result and the transcript of the
+// interaction attached.
+func (ia *Interaction) Run(ctx context.Context, r io.Reader) (scold.ExecutionResult, error) {
+	dir, err := os.MkdirTemp("", "scold-interactor-")
+	if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	inputPath := filepath.Join(dir, "input.txt")
+	outputPath := filepath.Join(dir, "output.txt")
+
+	err = writeFileFrom(inputPath, r)
+	if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: %v", err)
+	}
+
+	solution := ia.Solution.withTestLimits(ctx)
+
+	sol := solution.command()
+	inter := ia.Interactor.command(inputPath, outputPath)
+
+	output := newLimitedOutput(solution.OutputLimit)
+
+	var solStdout, solStderr, interStderr bytes.Buffer
+	sol.Stderr = output.writer(&solStderr)
+	inter.Stderr = &interStderr
+
+	solIn, solOut, err := stdioPipes(sol)
+	if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: solution: %v", err)
+	}
+
+	interIn, interOut, err := stdioPipes(inter)
+	if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: interactor: %v", err)
+	}
+
+	err = inter.Start()
+	if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: interactor: %v", err)
+	}
+
+	err = sol.Start()
+	if err != nil {
+		_ = killProcessGroup(inter.Process)
+		_ = inter.Wait()
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: solution: %v", err)
+	}
+
+	stopGuard := solution.guardLimits(sol.Process)
+
+	log := &transcript{budget: outputBudget{limit: solution.OutputLimit}}
+	relayDone := make(chan struct{})
+
+	go relay(interIn, io.TeeReader(solOut, output.writer(&solStdout)), log.side(solutionPrefix), relayDone)
+	go relay(solIn, interOut, log.side(interactorPrefix), relayDone)
+
+	// Killed processes close their pipes, so the relays will finish by
+	// themselves.
+	outputExceeded := false
+	cancelled, exceeded := ctx.Done(), output.exceeded
+	for doneCount := 0; doneCount != 2; {
+		select {
+		case <-cancelled:
+			_ = killProcessGroup(sol.Process)
+			_ = killProcessGroup(inter.Process)
+			cancelled = nil
+		case <-exceeded:
+			_ = killProcessGroup(sol.Process)
+			_ = killProcessGroup(inter.Process)
+			outputExceeded = true
+			exceeded = nil
+		case <-relayDone:
+			doneCount++
+		}
+	}
+
+	limitErr := stopGuard()
+
+	solResult, err := waitResult(sol)
+	if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: solution: %v", err)
+	}
+
+	interResult, err := waitResult(inter)
+	if err != nil {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: interactor: %v", err)
+	}
+
+	interOutput, err := os.ReadFile(outputPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return scold.ExecutionResult{}, fmt.Errorf("interaction: interactor: %v", err)
+	}
+
+	solResult.Stdout = solStdout.String()
+	solResult.Stderr = solStderr.String()
+	interResult.Stdout = string(interOutput)
+	interResult.Stderr = interStderr.String()
+
+	solResult.Interactor = &interResult
+	solResult.Transcript = log.String()
+
+	if outputExceeded {
+		return solResult, scold.OLError
+	}
+
+	if limitErr == nil {
+		limitErr = solution.checkLimits(solResult)
+	}
+
+	return solResult, limitErr
+}
+
+// InteractorChecker judges the tests by the exit code of the interactor
+// that the solution communicated with. It assumes that the interactor is
+// testlib-compatible.
+type InteractorChecker struct{}
+
+// Check derives the verdict from the interactor's exit code. The
+// interactor's stderr is the checker's message.
+func (InteractorChecker) Check(input, answer string, out scold.ExecutionResult) (scold.Verdict, string, error) {
+	if out.Interactor == nil {
+		return scold.IE, "", errors.New("solution was not run interactively")
+	}
+
+	return testlibVerdict(out.Interactor.ExitCode, strings.TrimSpace(out.Interactor.Stderr))
+}
+
+func writeFileFrom(path string, r io.Reader) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, r)
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func stdioPipes(cmd *exec.Cmd) (io.WriteCloser, io.ReadCloser, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stdin, stdout, nil
+}
+
+// waitResult waits for the command to exit and returns its exit code and
+// resource usage. It is not an error for the command to exit with a non-zero
+// code.
+func waitResult(cmd *exec.Cmd) (scold.ExecutionResult, error) {
+	err := cmd.Wait()
+
+	var out scold.ExecutionResult
+	if ee, ok := err.(*exec.ExitError); ok {
+		out.ExitCode = ee.ExitCode()
+	} else if err != nil {
+		return scold.ExecutionResult{}, err
+	}
+
+	recordUsage(&out, cmd.ProcessState)
+
+	return out, nil
+}
+
+// relay copies everything from src to dst while logging it. When dst is
+// closed prematurely, the rest of src is discarded, so that the writing
+// process doesn't block forever. Upon src's EOF, dst is closed.
+func relay(dst io.WriteCloser, src io.Reader, log io.Writer, done chan<- struct{}) {
+	_, err := io.Copy(dst, io.TeeReader(src, log))
+	if err != nil {
+		_, _ = io.Copy(io.Discard, src)
+	}
+
+	dst.Close()
+	done <- struct{}{}
+}
+
+// limitedOutput collects the output of a process written through its
+// writers as long as the budget allows. The rest is dropped, and exceeded
+// is closed, so that the process could be killed. Unlike listenPipe, the
+// writers never fail, since the process may still be reading its input.
+type limitedOutput struct {
+	budget   outputBudget
+	once     sync.Once
+	exceeded chan struct{}
+}
+
+func newLimitedOutput(limit scold.ByteSize) *limitedOutput {
+	return &limitedOutput{
+		budget:   outputBudget{limit: limit},
+		exceeded: make(chan struct{}),
+	}
+}
+
+func (o *limitedOutput) writer(w io.Writer) io.Writer {
+	return limitedWriter{o, w}
+}
+
+type limitedWriter struct {
+	output *limitedOutput
+	w      io.Writer
+}
+
+func (lw limitedWriter) Write(p []byte) (int, error) {
+	allowed := lw.output.budget.spend(len(p))
+	_, _ = lw.w.Write(p[:allowed])
+
+	if allowed != len(p) {
+		lw.output.once.Do(func() { close(lw.output.exceeded) })
+	}
+
+	return len(p), nil
+}
+
+// transcript is a thread-safe log of the data exchanged between two
+// processes. Each line is preceded by the prefix of the side that sent it.
+// The data that doesn't fit into the budget is not logged.
+type transcript struct {
+	mu     sync.Mutex
+	text   strings.Builder
+	budget outputBudget
+
+	// The prefix of the last unterminated line.
+	linePrefix string
+}
+
+func (t *transcript) side(prefix string) io.Writer {
+	return transcriptSide{t, prefix}
+}
+
+func (t *transcript) record(prefix string, p []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p = p[:t.budget.spend(len(p))]
+
+	for len(p) != 0 {
+		if t.linePrefix != prefix {
+			if t.linePrefix != "" {
+				t.text.WriteByte('\n')
+			}
+
+			t.text.WriteString(prefix)
+			t.linePrefix = prefix
+		}
+
+		end := bytes.IndexByte(p, '\n')
+		if end == -1 {
+			t.text.Write(p)
+			return
+		}
+
+		t.text.Write(p[:end+1])
+		t.linePrefix = ""
+		p = p[end+1:]
+	}
+}
+
+func (t *transcript) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.text.String()
+}
+
+type transcriptSide struct {
+	t      *transcript
+	prefix string
+}
+
+func (s transcriptSide) Write(p []byte) (int, error) {
+	s.t.record(s.prefix, p)
+	return len(p), nil
+}
@@ -0,0 +1,166 @@
+//go:build !windows
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/kuredoro/scold"
+)
+
+// The solution doubles the number it's given, and crashes after replying
+// to the negative ones.
+const doublingSolution = `#!/bin/sh
+read n
+echo $((n * 2))
+if [ "$n" -lt 0 ]; then
+	exit 3
+fi
+`
+
+// The input file holds the number to give and the expected reply.
+const doublingInteractor = `#!/bin/sh
+read n want < "$1"
+echo "$n"
+read got
+if [ "$got" != "$want" ]; then
+	echo "expected $want, got $got" >&2
+	exit 1
+fi
+echo "ok" >&2
+`
+
+func writeScript(t *testing.T, name, text string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(text), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestInteraction(t *testing.T) {
+	inputs := scold.Inputs{
+		Tests: []scold.Test{
+			{Input: "3 6\n"},
+			{Input: "3 7\n"},
+			{Input: "-1 -2\n"},
+		},
+		Config: scold.InputsConfig{
+			Tl: scold.NewPositiveDuration(10 * time.Second),
+		},
+	}
+
+	proc := &Interaction{
+		Solution:   &Executable{Path: writeScript(t, "solution.sh", doublingSolution)},
+		Interactor: &Executable{Path: writeScript(t, "interactor.sh", doublingInteractor)},
+	}
+
+	swatch := &scold.ConfigurableStopwatcher{
+		TL:    inputs.Config.Tl.Duration,
+		Clock: clockwork.NewRealClock(),
+	}
+
+	batch := scold.NewTestingBatch(inputs, proc, swatch, scold.NewThreadPool(1))
+	batch.Checker = InteractorChecker{}
+
+	batch.Run()
+
+	cases := []struct {
+		id         int
+		verdict    scold.Verdict
+		transcript string
+	}{
+		{1, scold.OK, "< 3\n> 6\n"},
+		{2, scold.WA, "< 3\n> 6\n"},
+		{3, scold.RE, "< -1\n> -2\n"},
+	}
+
+	for _, c := range cases {
+		result := batch.Results[c.id]
+
+		if result.Verdict != c.verdict {
+			t.Errorf("test %d: got verdict %v, want %v (error %v)", c.id, result.Verdict, c.verdict, result.Err)
+		}
+
+		if result.Out.Transcript != c.transcript {
+			t.Errorf("test %d: got transcript %q, want %q", c.id, result.Out.Transcript, c.transcript)
+		}
+	}
+
+	if msg := batch.Results[2].CheckerMessage; msg != "expected 7, got 6" {
+		t.Errorf("got checker message %q, want the interactor's stderr", msg)
+	}
+}
+
+func TestInteractionOutputLimit(t *testing.T) {
+	ia := &Interaction{
+		Solution: &Executable{
+			Path:        "/bin/sh",
+			Args:        []string{"-c", "while :; do echo spam; echo spam >&2; done"},
+			OutputLimit: 64 * scold.Kilobyte,
+		},
+		Interactor: &Executable{
+			Path: "/bin/sh",
+			Args: []string{"-c", "cat >/dev/null"},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out, err := ia.Run(ctx, strings.NewReader(""))
+
+	if err != scold.OLError {
+		t.Fatalf("got error %v, want %v", err, scold.OLError)
+	}
+
+	if size := len(out.Stdout) + len(out.Stderr); size != int(ia.Solution.OutputLimit) {
+		t.Errorf("got %d bytes of output, want exactly the limit of %d bytes", size, ia.Solution.OutputLimit)
+	}
+
+	if size := len(out.Transcript); size > int(ia.Solution.OutputLimit)*2 {
+		t.Errorf("got %d bytes of transcript, want it to be limited", size)
+	}
+}
+
+func TestTranscript(t *testing.T) {
+	t.Run("lines are interleaved", func(t *testing.T) {
+		log := &transcript{}
+		solution, interactor := log.side(solutionPrefix), log.side(interactorPrefix)
+
+		solution.Write([]byte("1\n2"))
+		interactor.Write([]byte("x\n"))
+		solution.Write([]byte("3"))
+		solution.Write([]byte("4\n"))
+		interactor.Write([]byte("y"))
+
+		want := "> 1\n> 2\n< x\n> 34\n< y"
+		if got := log.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("data over budget is dropped", func(t *testing.T) {
+		log := &transcript{budget: outputBudget{limit: 4}}
+		solution, interactor := log.side(solutionPrefix), log.side(interactorPrefix)
+
+		solution.Write([]byte("12\n"))
+		interactor.Write([]byte("xy\n"))
+		solution.Write([]byte("3\n"))
+
+		want := "> 12\n< x"
+		if got := log.String(); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
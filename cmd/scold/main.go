@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -15,6 +16,7 @@ import (
 	"github.com/jonboulle/clockwork"
 	"github.com/kuredoro/scold"
 	"github.com/kuredoro/scold/forwarders"
+	"github.com/kuredoro/scold/reporters"
 	"github.com/logrusorgru/aurora"
 	"github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
@@ -22,6 +24,29 @@ import (
 
 var stdout = colorable.NewColorableStdout()
 
+// cpuModeWallFactor defines how much longer than the time limit the
+// executable can run in the CPU time mode before being killed. It is
+// needed to deal with executables that sleep or wait for the input
+// forever.
+const cpuModeWallFactor = 3
+
+// backstopStopwatcher scales the time limits of the tests that override
+// it, so that in the CPU time mode, the wall-clock time limit remains
+// a backstop.
+type backstopStopwatcher struct {
+	scold.Stopwatcher
+	factor time.Duration
+}
+
+func (s backstopStopwatcher) WithTimeLimit(tl time.Duration) scold.Stopwatcher {
+	overrider, ok := s.Stopwatcher.(scold.TimeLimitOverrider)
+	if !ok {
+		return s
+	}
+
+	return backstopStopwatcher{overrider.WithTimeLimit(tl * s.factor), s.factor}
+}
+
 var errorLabel, warningLabel aurora.Value
 
 type JobCount int
@@ -45,15 +70,92 @@ func (jc *JobCount) UnmarshalText(b []byte) error {
 	return err
 }
 
+// ReportFormat is the format of the machine-readable report of the testing.
+type ReportFormat string
+
+// The supported report formats.
+const (
+	JSONReport      ReportFormat = "json"
+	JSONLinesReport ReportFormat = "jsonl"
+)
+
+func (f *ReportFormat) UnmarshalText(b []byte) error {
+	switch format := ReportFormat(b); format {
+	case JSONReport, JSONLinesReport:
+		*f = format
+		return nil
+	}
+
+	return fmt.Errorf("unknown report format %q, correct values are \"json\" and \"jsonl\"", b)
+}
+
+// newReporter creates the reporter that writes the report in the format to w.
+func (f ReportFormat) newReporter(w io.Writer) *reporters.JSONReporter {
+	if f == JSONLinesReport {
+		return reporters.NewJSONLinesReporter(w)
+	}
+
+	return reporters.NewJSONReporter(w)
+}
+
+// OutputFormat is the format of the usual output of the testing.
+type OutputFormat string
+
+// The supported output formats.
+const (
+	PrettyFormat OutputFormat = "pretty"
+	TAPFormat    OutputFormat = "tap"
+)
+
+func (f *OutputFormat) UnmarshalText(b []byte) error {
+	switch format := OutputFormat(b); format {
+	case PrettyFormat, TAPFormat:
+		*f = format
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format %q, correct values are \"pretty\" and \"tap\"", b)
+}
+
+// DiffMode is the way the answer and the output of the tests with WA are
+// shown.
+type DiffMode string
+
+// The supported diff modes.
+const (
+	StackedDiff    DiffMode = "stacked"
+	SideBySideDiff DiffMode = "side-by-side"
+)
+
+func (m *DiffMode) UnmarshalText(b []byte) error {
+	switch mode := DiffMode(b); mode {
+	case StackedDiff, SideBySideDiff:
+		*m = mode
+		return nil
+	}
+
+	return fmt.Errorf("unknown diff mode %q, correct values are \"stacked\" and \"side-by-side\"", b)
+}
+
 type appArgs struct {
-	Inputs        string   `arg:"-i" default:"inputs.txt" help:"file with tests"`
-	NoColors      bool     `arg:"--no-colors" help:"disable colored output"`
-	ForceColors   bool     `arg:"--force-colors" help:"print colors even in non-tty contexts"`
-	NoProgress    bool     `arg:"--no-progress" help:"disable progress bar"`
-	ForceProgress bool     `arg:"--force-progress" help:"print progress bar even in non-tty contexts"`
-	Jobs          JobCount `arg:"-j" default:"CPU_COUNT" placeholder:"COUNT" help:"Number of tests to run concurrently"`
-	Executable    string   `arg:"positional,required"`
-	Args          []string `arg:"positional" placeholder:"ARG"`
+	Inputs        string                  `arg:"-i" default:"inputs.txt" help:"file or directory with tests"`
+	NoColors      bool                    `arg:"--no-colors" help:"disable colored output"`
+	ForceColors   bool                    `arg:"--force-colors" help:"print colors even in non-tty contexts"`
+	NoProgress    bool                    `arg:"--no-progress" help:"disable progress bar"`
+	ForceProgress bool                    `arg:"--force-progress" help:"print progress bar even in non-tty contexts"`
+	Jobs          JobCount                `arg:"-j" default:"CPU_COUNT" placeholder:"COUNT" help:"Number of tests to run concurrently"`
+	Checker       string                  `arg:"--checker" placeholder:"CHECKER" help:"testlib-style checker executable to judge outputs with"`
+	Interactor    string                  `arg:"--interactor" placeholder:"INTERACTOR" help:"testlib-style interactor executable to run the executable against"`
+	TlMode        *scold.TimeLimitMode    `arg:"--tl-mode" placeholder:"MODE" help:"limit the wall or cpu time, overrides tl_mode in the inputs file"`
+	Patterns      []scold.TestFilePattern `arg:"--pattern" placeholder:"INPUT:ANSWER" help:"how the test files are named if the inputs is a directory (default: *.in:*.ans *.in:*.out *:*.a)"`
+	Format        OutputFormat            `arg:"--format" default:"pretty" placeholder:"FORMAT" help:"format of the usual output, pretty or tap"`
+	Diff          DiffMode                `arg:"--diff" default:"stacked" placeholder:"MODE" help:"how to show the answer and the output of WA tests, stacked or side-by-side"`
+	Report        ReportFormat            `arg:"--report" placeholder:"FORMAT" help:"print a machine-readable report instead of the usual output, json or jsonl"`
+	ReportFile    string                  `arg:"--report-file" placeholder:"FILE" help:"write the report to FILE and keep the usual output (default format: json)"`
+	JUnit         string                  `arg:"--junit" placeholder:"FILE" help:"write a JUnit XML report to FILE"`
+	HTML          string                  `arg:"--html" placeholder:"FILE" help:"write an HTML report to FILE"`
+	Executable    string                  `arg:"positional,required"`
+	Args          []string                `arg:"positional" placeholder:"ARG"`
 }
 
 var args appArgs
@@ -117,7 +219,10 @@ func warningPrintf(format string, args ...any) {
     fmt.Fprintf(stdout, "%v: " + format + "\n", printArgs...)
 }
 
-func init() {
+// setup parses the command-line arguments and configures the output and
+// the defaults accordingly. It is not an init function, so that the tests
+// of this package could run.
+func setup() {
 	mustParse(&args)
 
 	if args.NoColors && args.ForceColors {
@@ -139,6 +244,17 @@ func init() {
 		args.NoProgress = true
 	}
 
+	if args.ReportFile != "" && args.Report == "" {
+		args.Report = JSONReport
+	}
+
+	// The machine-readable output must not be mixed with anything else,
+	// so the errors and the warnings go to stderr.
+	if reportToStdout() || args.Format == TAPFormat {
+		args.NoProgress = true
+		stdout = colorable.NewColorableStderr()
+	}
+
 	if args.NoColors {
 		scold.Au = aurora.NewAurora(false)
 	}
@@ -149,49 +265,127 @@ func init() {
 	scold.DefaultInputsConfig = scold.InputsConfig{
 		Tl:   scold.NewPositiveDuration(6 * time.Second),
 		Prec: 8,
+		Ol:   64 * scold.Megabyte,
 	}
 }
 
-func main() {
-	inputsPath, err := filepath.Abs(args.Inputs)
+// reportToStdout tells whether the machine-readable report replaces the
+// usual output.
+func reportToStdout() bool {
+	return args.Report != "" && args.ReportFile == ""
+}
+
+// reportListener is a testing event listener that writes a report and
+// remembers the write errors.
+type reportListener interface {
+	scold.TestingEventListener
+	Err() error
+}
+
+// fileReport is a report written to a file or to stdout.
+type fileReport struct {
+	reporter reportListener
+	file     *os.File
+}
+
+// close closes the file unless it's stdout and returns the first error
+// encountered while writing the report.
+func (r fileReport) close() error {
+	if err := r.reporter.Err(); err != nil {
+		return err
+	}
+
+	if r.file == os.Stdout {
+		return nil
+	}
+
+	return r.file.Close()
+}
+
+// createReportFile creates the report file or exits on failure.
+func createReportFile(path string) *os.File {
+	file, err := os.Create(path)
 	if err != nil {
-		errorPrintf("retreive inputs absolute path: %v", err)
-        os.Exit(1)
+		errorPrintf("create report file: %v", err)
+		os.Exit(1)
 	}
 
-	inputs, scanErrs := readInputs(inputsPath)
-	if scanErrs != nil {
-		var lineRangeErrorType *scold.LineRangeError
-		if len(scanErrs) == 1 && !errors.As(scanErrs[0], &lineRangeErrorType) {
-            errorPrintf("load tests: %v", scanErrs[0])
-            os.Exit(1)
-		}
+	return file
+}
 
-		lineErrs := make([]*scold.LineRangeError, len(scanErrs))
-		for i, scanErr := range scanErrs {
-			ok := errors.As(scanErr, &lineErrs[i])
-			if !ok {
-                panic(fmt.Sprintf("internal bug: some parse errors don't have line information (%v)", scanErr))
+// lineErrorAt is a LineRangeError found in the file at path.
+type lineErrorAt struct {
+	path string
+	*scold.LineRangeError
+}
+
+// reportInputsErrors prints the errors and the warnings produced while
+// loading the tests and tells whether there were any errors.
+func reportInputsErrors(scanErrs []error) (hadErrors bool) {
+	var lineErrs []lineErrorAt
+	for _, err := range scanErrs {
+		var lineErr *scold.LineRangeError
+		if errors.As(err, &lineErr) {
+			// The errors in the config of a directory suite are wrapped in
+			// FileError.
+			path := args.Inputs
+			var fileErr *scold.FileError
+			if errors.As(err, &fileErr) {
+				path = filepath.Join(args.Inputs, fileErr.Path)
 			}
+
+			lineErrs = append(lineErrs, lineErrorAt{path, lineErr})
+			continue
 		}
 
-		sort.Slice(lineErrs, func(i, j int) bool {
-			return lineErrs[i].Begin < lineErrs[j].Begin
-		})
-
-        hadErrors := false
-		for _, err := range lineErrs {
-            if w := scold.StringWarning(""); errors.As(err, &w) {
-                warningPrintf("%s:%d: %v\n%s", args.Inputs, err.Begin, err.Err, err.CodeSnippet())
-            } else {
-                errorPrintf("%s:%d: %v\n%s", args.Inputs, err.Begin, err.Err, err.CodeSnippet())
-                hadErrors = true
-            }
+		report := errorPrintf
+		if w := scold.StringWarning(""); errors.As(err, &w) {
+			report = warningPrintf
+		} else {
+			hadErrors = true
 		}
 
-        if hadErrors {
-            os.Exit(1)
-        }
+		var fileErr *scold.FileError
+		if errors.As(err, &fileErr) {
+			report("%s: %v", filepath.Join(args.Inputs, fileErr.Path), fileErr.Err)
+		} else {
+			report("load tests: %v", err)
+		}
+	}
+
+	sort.Slice(lineErrs, func(i, j int) bool {
+		return lineErrs[i].Begin < lineErrs[j].Begin
+	})
+
+	for _, err := range lineErrs {
+		if w := scold.StringWarning(""); errors.As(err.LineRangeError, &w) {
+			warningPrintf("%s:%d: %v\n%s", err.path, err.Begin, err.Err, err.CodeSnippet())
+		} else {
+			errorPrintf("%s:%d: %v\n%s", err.path, err.Begin, err.Err, err.CodeSnippet())
+			hadErrors = true
+		}
+	}
+
+	return hadErrors
+}
+
+func main() {
+	setup()
+
+	inputsPath, err := filepath.Abs(args.Inputs)
+	if err != nil {
+		errorPrintf("retreive inputs absolute path: %v", err)
+        os.Exit(1)
+	}
+
+	patterns := args.Patterns
+	if len(patterns) == 0 {
+		patterns = scold.DefaultTestFilePatterns
+	}
+
+	inputs, scanErrs := readInputs(inputsPath, patterns)
+	if scanErrs != nil && reportInputsErrors(scanErrs) {
+		os.Exit(1)
 	}
 
 	execPath, err := findFile(args.Executable)
@@ -211,26 +405,77 @@ func main() {
         os.Exit(1)
 	}
 
-	proc := &Executable{
-		Path: execPath,
-		Args: args.Args,
+	if args.Checker != "" && args.Interactor != "" {
+		errorPrintf("--checker and --interactor cannot be used together, the interactor is the checker")
+		os.Exit(1)
+	}
+
+	if reportToStdout() && args.Format == TAPFormat {
+		errorPrintf("--report prints to stdout and cannot be used with --format tap, use --report-file instead")
+		os.Exit(1)
 	}
 
-	swatch := &scold.ConfigurableStopwatcher{
-		TL:    inputs.Config.Tl.Duration,
+	if args.TlMode != nil {
+		inputs.Config.TlMode = *args.TlMode
+	}
+
+	exe := &Executable{
+		Path:        execPath,
+		Args:        args.Args,
+		MemoryLimit: inputs.Config.Ml,
+		OutputLimit: inputs.Config.Ol,
+		TlMode:      inputs.Config.TlMode,
+	}
+
+	wallTl := inputs.Config.Tl.Duration
+	if inputs.Config.TlMode == scold.CPUTimeMode {
+		exe.CPULimit = inputs.Config.Tl.Duration
+		wallTl *= cpuModeWallFactor
+	}
+
+	var proc scold.Processer = exe
+
+	if args.Interactor != "" {
+		interactorPath, err := findFile(args.Interactor)
+		if err != nil {
+			errorPrintf("find interactor: %v", err)
+			os.Exit(1)
+		}
+
+		proc = &Interaction{
+			Solution:   exe,
+			Interactor: &Executable{Path: interactorPath},
+		}
+	}
+
+	var swatch scold.Stopwatcher = &scold.ConfigurableStopwatcher{
+		TL:    wallTl,
 		Clock: clockwork.NewRealClock(),
 	}
+	if inputs.Config.TlMode == scold.CPUTimeMode {
+		swatch = backstopStopwatcher{swatch, cpuModeWallFactor}
+	}
 	pool := scold.NewThreadPool(int(args.Jobs))
 
 	batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
 
-	if inputs.Config.Tl.Duration == 0 {
-		fmt.Println("time limit: infinity")
-	} else {
-		fmt.Printf("time limit: %v\n", inputs.Config.Tl)
+	if args.Checker != "" {
+		checkerPath, err := findFile(args.Checker)
+		if err != nil {
+			errorPrintf("find checker: %v", err)
+			os.Exit(1)
+		}
+
+		batch.Checker = &ExternalChecker{Path: checkerPath}
+	}
+
+	if args.Interactor != "" {
+		batch.Checker = InteractorChecker{}
+	}
+
+	if !reportToStdout() && args.Format == PrettyFormat {
+		printConfig(inputs, batch)
 	}
-	fmt.Printf("floating point precision: %d digit(s)\n", batch.Lx.Precision)
-	fmt.Printf("job count: %d\n", args.Jobs)
 
 	var progressBar *ProgressBar
 	if !args.NoProgress {
@@ -242,16 +487,68 @@ func main() {
 		}
 	}
 
-	cliPrinter := NewPrettyPrinter(scold.Au)
-	cliPrinter.Bar = progressBar
+	var listeners forwarders.BroadcastForwarder
+
+	var reports []fileReport
+
+	if !reportToStdout() && args.Format == PrettyFormat {
+		cliPrinter := NewPrettyPrinter(scold.Au)
+		cliPrinter.Bar = progressBar
+		cliPrinter.SideBySide = args.Diff == SideBySideDiff
+		cliPrinter.Width = terminalWidth()
+		cliPrinter.Lexer = batch.Lexer
+
+		listeners = append(listeners, cliPrinter)
+	}
+
+	if args.Format == TAPFormat {
+		tapPrinter := reporters.NewTAPReporter(os.Stdout, len(inputs.Tests))
+		reports = append(reports, fileReport{tapPrinter, os.Stdout})
+	}
+
+	if args.Report != "" {
+		reportFile := os.Stdout
+		if args.ReportFile != "" {
+			reportFile = createReportFile(args.ReportFile)
+		}
+
+		reporter := args.Report.newReporter(reportFile)
+
+		reports = append(reports, fileReport{reporter, reportFile})
+	}
+
+	if args.JUnit != "" {
+		reportFile := createReportFile(args.JUnit)
+		reporter := reporters.NewJUnitReporter(reportFile, args.Inputs)
 
-	asyncF := forwarders.NewAsyncEventForwarder(cliPrinter, 100)
+		reports = append(reports, fileReport{reporter, reportFile})
+	}
+
+	if args.HTML != "" {
+		reportFile := createReportFile(args.HTML)
+		reporter := reporters.NewHTMLReporter(reportFile, args.Inputs)
+
+		reports = append(reports, fileReport{reporter, reportFile})
+	}
+
+	for _, report := range reports {
+		listeners = append(listeners, report.reporter)
+	}
+
+	asyncF := forwarders.NewAsyncEventForwarder(listeners, 100)
 	batch.Listener = asyncF
 
 	batch.Run()
 
 	asyncF.Wait()
 
+	for _, report := range reports {
+		if err := report.close(); err != nil {
+			errorPrintf("write report: %v", err)
+			os.Exit(1)
+		}
+	}
+
     allOK := true
     for i := range batch.Results {
         if batch.Results[i].Verdict != scold.OK {
@@ -264,3 +561,42 @@ func main() {
         os.Exit(1)
     }
 }
+
+// printConfig prints the configuration the tests are run with.
+func printConfig(inputs scold.Inputs, batch *scold.TestingBatch) {
+	if inputs.Config.Tl.Duration == 0 {
+		fmt.Println("time limit: infinity")
+	} else {
+		fmt.Printf("time limit: %v (%v)\n", inputs.Config.Tl, inputs.Config.TlMode)
+	}
+	if inputs.Config.Ml != 0 {
+		fmt.Printf("memory limit: %v\n", inputs.Config.Ml)
+		if !memoryLimitSupported {
+			warningPrintf("memory limit is not enforced on %s", runtime.GOOS)
+		}
+	}
+	if inputs.Config.Ol != 0 {
+		fmt.Printf("output limit: %v\n", inputs.Config.Ol)
+	}
+	if batch.Lx.AbsEps != 0 || batch.Lx.RelEps != 0 {
+		fmt.Printf("floating point tolerance: %g absolute, %g relative\n", batch.Lx.AbsEps, batch.Lx.RelEps)
+	} else {
+		fmt.Printf("floating point precision: %d digit(s)\n", batch.Lx.Precision)
+	}
+	if batch.Lx.IgnoreCase {
+		fmt.Println("letter case: ignored")
+	}
+	if batch.Lx.Order != scold.StrictOrder {
+		fmt.Printf("order: %v\n", batch.Lx.Order)
+	}
+	if batch.Lx.Mode != scold.CompareTokens {
+		fmt.Printf("compare: %v\n", batch.Lx.Mode)
+	}
+	if args.Checker != "" {
+		fmt.Printf("checker: %s\n", args.Checker)
+	}
+	if args.Interactor != "" {
+		fmt.Printf("interactor: %s\n", args.Interactor)
+	}
+	fmt.Printf("job count: %d\n", args.Jobs)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/kuredoro/scold"
+)
+
+func TestBackstopStopwatcher(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+
+	var swatch scold.Stopwatcher = backstopStopwatcher{
+		&scold.ConfigurableStopwatcher{TL: 3 * time.Second, Clock: clock},
+		cpuModeWallFactor,
+	}
+
+	// The overrides must be scaled no matter how many times they're made.
+	for _, tl := range []time.Duration{time.Second, 2 * time.Second} {
+		swatch = swatch.(scold.TimeLimitOverrider).WithTimeLimit(tl)
+
+		backstop, ok := swatch.(backstopStopwatcher)
+		if !ok {
+			t.Fatalf("got stopwatcher of type %T, want backstopStopwatcher", swatch)
+		}
+
+		want := tl * cpuModeWallFactor
+		if got := backstop.Stopwatcher.(*scold.ConfigurableStopwatcher).TL; got != want {
+			t.Errorf("got time limit %v for the override of %v, want %v", got, tl, want)
+		}
+	}
+}
+
+func TestReportFormat(t *testing.T) {
+	cases := []struct {
+		text  string
+		lines bool
+	}{
+		{"json", false},
+		{"jsonl", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.text, func(t *testing.T) {
+			var format ReportFormat
+			err := format.UnmarshalText([]byte(c.text))
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+
+			if reporter := format.newReporter(io.Discard); reporter.Lines != c.lines {
+				t.Errorf("got Lines %v, want %v", reporter.Lines, c.lines)
+			}
+		})
+	}
+
+	t.Run("unknown format is forbidden", func(t *testing.T) {
+		var format ReportFormat
+		if err := format.UnmarshalText([]byte("xml")); err == nil {
+			t.Error("got no error")
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/atomicgo/cursor"
 	"github.com/kuredoro/scold"
@@ -18,17 +19,26 @@ const missingNewlineColor = aurora.MagentaFg
 type PrettyPrinter struct {
 	Bar        *ProgressBar
 	verdictStr map[scold.Verdict]aurora.Value
+
+	// SideBySide makes the answer and the output of WA tests be printed in
+	// two columns that fit into Width characters. Their lines are aligned
+	// using the lexer returned by Lexer for the test.
+	SideBySide bool
+	Width      int
+	Lexer      func(test *scold.Test) *scold.Lexer
 }
 
 func NewPrettyPrinter(au aurora.Aurora) *PrettyPrinter {
 	p := &PrettyPrinter{}
 
 	p.verdictStr = map[scold.Verdict]aurora.Value{
-		scold.OK: au.Bold("OK").Green(),
-		scold.IE: au.Bold("IE").Bold(),
-		scold.WA: au.Bold("WA").BrightRed(),
-		scold.RE: au.Bold("RE").Magenta(),
-		scold.TL: au.Bold("TL").Yellow(),
+		scold.OK:  au.Bold("OK").Green(),
+		scold.IE:  au.Bold("IE").Bold(),
+		scold.WA:  au.Bold("WA").BrightRed(),
+		scold.RE:  au.Bold("RE").Magenta(),
+		scold.TL:  au.Bold("TL").Yellow(),
+		scold.ML:  au.Bold("ML").Cyan(),
+		scold.OLE: au.Bold("OLE").Blue(),
 	}
 
 	return p
@@ -46,13 +56,30 @@ func (p *PrettyPrinter) TestFinished(test *scold.Test, result *scold.TestResult)
 
 	verdict := result.Verdict
 
-	seconds := result.Time.Round(time.Millisecond).Seconds()
-	fmt.Fprintf(str, "--- %s:\tTest %d (%.3fs)\n", p.verdictStr[verdict], result.ID, seconds)
+	fmt.Fprintf(str, "--- %s:\tTest %d (%s)\n", p.verdictStr[verdict], result.ID,
+		formatUsage(result.Time, result.Out.CPUTime(), result.Out.PeakMemory))
 
 	if verdict != scold.OK {
 		fmt.Fprintf(str, "Input:\n%s\n", test.Input)
 
-		fmt.Fprintf(str, "Answer:\n%s\n", scold.DumpLexemes(result.RichAnswer, diffColor))
+		answerTitle := "Answer"
+		if len(test.AltOutputs) != 0 {
+			if verdict == scold.WA {
+				answerTitle = fmt.Sprintf("Closest answer (%d of %d)", result.AnswerIndex+1, len(test.AltOutputs)+1)
+			} else {
+				answerTitle = fmt.Sprintf("Answer (1 of %d)", len(test.AltOutputs)+1)
+			}
+		}
+
+		// The output of an interactive solution is meant for the
+		// interactor, not for comparison with the answer.
+		sideBySideDiff := p.SideBySide && verdict == scold.WA && result.Out.Interactor == nil
+
+		if sideBySideDiff {
+			fmt.Fprintf(str, "%s\n", sideBySide(p.Lexer(test), answerTitle, result.RichAnswer, result.RichOut, p.Width))
+		} else {
+			fmt.Fprintf(str, "%s:\n%s\n", answerTitle, scold.DumpLexemes(result.RichAnswer, diffColor))
+		}
 
 		if verdict == scold.RE {
             if util.IsPossiblyNegative(result.Out.ExitCode) {
@@ -61,17 +88,28 @@ func (p *PrettyPrinter) TestFinished(test *scold.Test, result *scold.TestResult)
             } else {
                 fmt.Fprintf(str, "Exit code: %d\n\n", result.Out.ExitCode)
             }
-			fmt.Fprint(str, "Output:\n")
-			printAlwaysWithNewline(str, result.Out.Stdout)
+			if result.Out.Interactor == nil {
+				fmt.Fprint(str, "Output:\n")
+				printAlwaysWithNewline(str, result.Out.Stdout)
+			}
 			fmt.Fprint(str, "Stderr:\n")
 			printAlwaysWithNewline(str, result.Out.Stderr)
 		} else if verdict == scold.WA {
-			fmt.Fprintf(str, "Output:\n%s\n", scold.DumpLexemes(result.RichOut, diffColor))
+			if result.Out.Interactor == nil && !sideBySideDiff {
+				fmt.Fprintf(str, "Output:\n%s\n", scold.DumpLexemes(result.RichOut, diffColor))
+			}
 			if result.Out.Stderr != "" {
 				fmt.Fprintf(str, "Stderr:\n%s\n", result.Out.Stderr)
 			}
-		} else if verdict == scold.TL {
-			if result.Out.Stdout != "" {
+			if result.CheckerMessage != "" {
+				checkerName := "Checker"
+				if result.Out.Interactor != nil {
+					checkerName = "Interactor"
+				}
+				fmt.Fprintf(str, "%s:\n%s\n\n", checkerName, result.CheckerMessage)
+			}
+		} else if verdict == scold.TL || verdict == scold.ML {
+			if result.Out.Stdout != "" && result.Out.Interactor == nil {
 				fmt.Fprint(str, "Output:\n")
 				printAlwaysWithNewline(str, result.Out.Stdout)
 			}
@@ -80,9 +118,22 @@ func (p *PrettyPrinter) TestFinished(test *scold.Test, result *scold.TestResult)
 				fmt.Fprint(str, "Stderr:\n")
 				printAlwaysWithNewline(str, result.Out.Stderr)
 			}
+		} else if verdict == scold.OLE {
+			fmt.Fprint(str, "Output (truncated):\n")
+			printAlwaysWithNewline(str, truncateMiddle(result.Out.Stdout, truncatedEdgeSize))
+
+			if result.Out.Stderr != "" {
+				fmt.Fprint(str, "Stderr (truncated):\n")
+				printAlwaysWithNewline(str, truncateMiddle(result.Out.Stderr, truncatedEdgeSize))
+			}
 		} else if verdict == scold.IE {
 			fmt.Fprintf(str, "Error:\n%v\n\n", result.Err)
 		}
+
+		if result.Out.Transcript != "" {
+			fmt.Fprint(str, "Transcript:\n")
+			printAlwaysWithNewline(str, result.Out.Transcript)
+		}
 	}
 
 	if p.Bar != nil {
@@ -110,20 +161,80 @@ func (p *PrettyPrinter) SuiteFinished(b *scold.TestingBatch) {
 	}
 
 	passCount := 0
+	var maxTime, maxCPUTime time.Duration
+	var maxMemory scold.ByteSize
 	for _, r := range b.Results {
 		if r.Verdict == scold.OK {
 			passCount++
 		}
+
+		if r.Time > maxTime {
+			maxTime = r.Time
+		}
+
+		if r.Out.CPUTime() > maxCPUTime {
+			maxCPUTime = r.Out.CPUTime()
+		}
+
+		if r.Out.PeakMemory > maxMemory {
+			maxMemory = r.Out.PeakMemory
+		}
 	}
 
+	maxUsage := formatUsage(maxTime, maxCPUTime, maxMemory)
+
 	if passCount == len(b.Results) {
-		fmt.Fprintln(stdout, scold.Au.Bold("OK").Green())
+		fmt.Fprintf(stdout, "%s (max %s)\n", scold.Au.Bold("OK").Green(), maxUsage)
 	} else {
-		fmt.Fprintln(stdout, scold.Au.Bold("FAIL").Red())
+		fmt.Fprintf(stdout, "%s (max %s)\n", scold.Au.Bold("FAIL").Red(), maxUsage)
 		fmt.Fprintf(stdout, "%d/%d passed\n", passCount, len(b.Results))
 	}
 }
 
+// formatUsage renders the wall time, the CPU time and the memory consumed
+// by a test. If the resource usage is unknown, only the wall time is shown.
+func formatUsage(wall, cpu time.Duration, memory scold.ByteSize) string {
+	usage := fmt.Sprintf("%.3fs", wall.Round(time.Millisecond).Seconds())
+
+	if cpu == 0 && memory == 0 {
+		return usage
+	}
+
+	usage += fmt.Sprintf(" wall, %.3fs cpu", cpu.Round(time.Millisecond).Seconds())
+
+	if memory != 0 {
+		usage += ", " + memory.String()
+	}
+
+	return usage
+}
+
+// truncatedEdgeSize is the number of bytes shown from the beginning and
+// from the end of a huge output.
+const truncatedEdgeSize = 512
+
+// truncateMiddle leaves only edgeSize bytes from each side of the text,
+// replacing the rest with a note of how much was omitted. The cuts are
+// made on rune boundaries.
+func truncateMiddle(text string, edgeSize int) string {
+	if len(text) <= 2*edgeSize {
+		return text
+	}
+
+	headEnd := edgeSize
+	for headEnd > 0 && !utf8.RuneStart(text[headEnd]) {
+		headEnd--
+	}
+
+	tailStart := len(text) - edgeSize
+	for tailStart < len(text) && !utf8.RuneStart(text[tailStart]) {
+		tailStart++
+	}
+
+	omitted := scold.ByteSize(tailStart - headEnd)
+	return fmt.Sprintf("%s\n... %v omitted ...\n%s", text[:headEnd], omitted, text[tailStart:])
+}
+
 func printAlwaysWithNewline(r io.Writer, text string) {
 	fmt.Fprint(r, text)
 	if text != "" && text[len(text)-1] != '\n' {
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kuredoro/scold"
+)
+
+func TestFormatUsage(t *testing.T) {
+	cases := []struct {
+		name   string
+		wall   time.Duration
+		cpu    time.Duration
+		memory scold.ByteSize
+		want   string
+	}{
+		{"unknown usage", 1234567 * time.Microsecond, 0, 0, "1.235s"},
+		{"cpu only", 1500 * time.Millisecond, 1200 * time.Millisecond, 0, "1.500s wall, 1.200s cpu"},
+		{"cpu and memory", 120 * time.Millisecond, 101 * time.Millisecond, 12 * scold.Megabyte, "0.120s wall, 0.101s cpu, 12.0MB"},
+		{"memory only", 120 * time.Millisecond, 0, 512 * scold.Kilobyte, "0.120s wall, 0.000s cpu, 512.0KB"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := formatUsage(c.wall, c.cpu, c.memory)
+
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
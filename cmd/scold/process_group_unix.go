@@ -0,0 +1,28 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup makes the command start in a new process group, whose
+// ID is the same as the PID of the command's process.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the process together with all of its descendants
+// that did not leave its process group. It is not an error if the group
+// has already exited.
+func killProcessGroup(proc *os.Process) error {
+	err := syscall.Kill(-proc.Pid, syscall.SIGKILL)
+	if errors.Is(err, syscall.ESRCH) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing, since the processes on Windows are killed
+// one by one.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills only the process itself, the descendants are left
+// running. It is not an error if the process has already exited.
+func killProcessGroup(proc *os.Process) error {
+	err := proc.Kill()
+	if errors.Is(err, os.ErrProcessDone) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kuredoro/scold"
+)
+
+// memoryLimitSupported tells whether the memory limit can be enforced on
+// this platform.
+const memoryLimitSupported = true
+
+const resourcePollInterval = 5 * time.Millisecond
+
+// The kernel reports the CPU time in clock ticks. USER_HZ is 100 on all
+// architectures Linux supports.
+const clockTicksPerSecond = 100
+
+// watchResources polls the memory usage and the consumed CPU time of the
+// process group led by the process and kills the group as soon as either
+// exceeds its limit. Zero limits are not enforced. Rlimits are not used
+// for the memory, because they restrict the virtual memory of each process
+// separately, and runtimes like Go or V8 reserve much more of it than they
+// actually use. The returned channel receives either MLError or TLError
+// after the kill or nil after stop is closed.
+func watchResources(proc *os.Process, memory scold.ByteSize, cpu time.Duration, stop <-chan struct{}) <-chan error {
+	exceeded := make(chan error, 1)
+
+	go func() {
+		ticker := time.NewTicker(resourcePollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				exceeded <- nil
+				return
+			case <-ticker.C:
+			}
+
+			members := groupMembers(proc.Pid)
+
+			if memory != 0 {
+				usage, err := readGroupMemory(members)
+				if err == nil && usage > memory {
+					_ = killProcessGroup(proc)
+					exceeded <- scold.MLError
+					return
+				}
+			}
+
+			if cpu != 0 {
+				spent, err := readGroupCPUTime(members)
+				if err == nil && spent > cpu {
+					_ = killProcessGroup(proc)
+					exceeded <- scold.TLError
+					return
+				}
+			}
+		}
+	}()
+
+	return exceeded
+}
+
+// procStat is the part of the process's stat file in procfs that is
+// needed to track its resource usage.
+type procStat struct {
+	pgrp    int
+	cpuTime time.Duration
+}
+
+// readProcStat reads the stat file of the process in procfs.
+func readProcStat(pid int) (procStat, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return procStat{}, err
+	}
+
+	ps, ok := parseProcStat(stat)
+	if !ok {
+		return procStat{}, fmt.Errorf("malformed /proc/%d/stat", pid)
+	}
+
+	return ps, nil
+}
+
+// parseProcStat extracts the process group ID and the time the process and
+// its waited-for children spent in user and kernel mode from the contents
+// of its stat file.
+func parseProcStat(stat []byte) (ps procStat, ok bool) {
+	// The second field is the executable's name in parentheses, which may
+	// contain spaces, so the fields are counted from the closing one.
+	nameEnd := bytes.LastIndexByte(stat, ')')
+	if nameEnd == -1 {
+		return procStat{}, false
+	}
+
+	// The fields after the name start from the 3rd one. pgrp is the 5th,
+	// utime, stime, cutime and cstime are the 14th to the 17th.
+	fields := strings.Fields(string(stat[nameEnd+1:]))
+	if len(fields) < 15 {
+		return procStat{}, false
+	}
+
+	pgrp, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return procStat{}, false
+	}
+
+	var ticks uint64
+	for _, field := range fields[11:15] {
+		n, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return procStat{}, false
+		}
+
+		ticks += n
+	}
+
+	return procStat{pgrp, time.Duration(ticks) * time.Second / clockTicksPerSecond}, true
+}
+
+// readGroupCPUTime sums the CPU time spent by the members of the process
+// group and by their children that have exited. The first member is the
+// group's leader.
+func readGroupCPUTime(members []int) (total time.Duration, err error) {
+	for _, member := range members {
+		ps, err := readProcStat(member)
+		if err != nil {
+			// The descendants may exit at any moment.
+			if member == members[0] {
+				return 0, err
+			}
+
+			continue
+		}
+
+		total += ps.cpuTime
+	}
+
+	return total, nil
+}
+
+// groupMembers returns the process and those of its descendants that stay
+// in its process group, which is assumed to be led by the process. The
+// descendants are found using the children files in procfs, so the ones
+// that got orphaned are missed, although they are still killed with the
+// group.
+func groupMembers(pid int) (members []int) {
+	members = append(members, pid)
+
+	for i := 0; i < len(members); i++ {
+		for _, child := range readChildren(members[i]) {
+			ps, err := readProcStat(child)
+			if err == nil && ps.pgrp == pid {
+				members = append(members, child)
+			}
+		}
+	}
+
+	return
+}
+
+// readChildren reads the PIDs of the children of each of the process's
+// threads. If the kernel doesn't provide the children files, there are no
+// children.
+func readChildren(pid int) (children []int) {
+	files, _ := filepath.Glob(fmt.Sprintf("/proc/%d/task/*/children", pid))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+
+		for _, field := range strings.Fields(string(data)) {
+			child, err := strconv.Atoi(field)
+			if err == nil {
+				children = append(children, child)
+			}
+		}
+	}
+
+	return
+}
+
+// readGroupMemory estimates the memory used by the members of the process
+// group. It's the sum of their current resident set sizes, unless one of
+// them alone used more at some point. The first member is the group's
+// leader.
+func readGroupMemory(members []int) (scold.ByteSize, error) {
+	var total, peak scold.ByteSize
+
+	for _, member := range members {
+		rss, hwm, err := readMemory(member)
+		if err != nil {
+			// The descendants may exit at any moment.
+			if member == members[0] {
+				return 0, err
+			}
+
+			continue
+		}
+
+		total += rss
+		if hwm > peak {
+			peak = hwm
+		}
+	}
+
+	if peak > total {
+		return peak, nil
+	}
+
+	return total, nil
+}
+
+// readMemory reads the current resident set size and its high water mark
+// from the VmRSS and VmHWM fields of the process's status file in procfs.
+func readMemory(pid int) (rss, hwm scold.ByteSize, err error) {
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	found := 0
+	s := bufio.NewScanner(bytes.NewReader(status))
+	for s.Scan() && found != 2 {
+		var field *scold.ByteSize
+		switch {
+		case strings.HasPrefix(s.Text(), "VmRSS:"):
+			field = &rss
+		case strings.HasPrefix(s.Text(), "VmHWM:"):
+			field = &hwm
+		default:
+			continue
+		}
+
+		// The line looks like "VmHWM:	    1234 kB"
+		fields := strings.Fields(s.Text())
+		if len(fields) != 3 {
+			break
+		}
+
+		kilobytes, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		*field = scold.ByteSize(kilobytes) * scold.Kilobyte
+		found++
+	}
+
+	if found != 2 {
+		return 0, 0, fmt.Errorf("no VmRSS or VmHWM in /proc/%d/status", pid)
+	}
+
+	return rss, hwm, nil
+}
+
+// recordUsage fills in the resources consumed by the exited process.
+func recordUsage(out *scold.ExecutionResult, state *os.ProcessState) {
+	out.UserTime = state.UserTime()
+	out.SystemTime = state.SystemTime()
+
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return
+	}
+
+	out.PeakMemory = scold.ByteSize(rusage.Maxrss) * scold.Kilobyte
+	out.VoluntaryCtxSwitches = int64(rusage.Nvcsw)
+	out.InvoluntaryCtxSwitches = int64(rusage.Nivcsw)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuredoro/scold"
+)
+
+func TestExecutableMemoryLimit(t *testing.T) {
+	exe := helperExecutable(t, "allocate")
+	exe.MemoryLimit = 64 * scold.Megabyte
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := exe.Run(ctx, strings.NewReader(""))
+	elapsed := time.Since(start)
+
+	if err != scold.MLError {
+		t.Fatalf("got error %v, want %v", err, scold.MLError)
+	}
+
+	if elapsed > 10*time.Second {
+		t.Errorf("Run returned after %v, want the process to be killed promptly", elapsed)
+	}
+}
+
+func TestExecutableCPULimit(t *testing.T) {
+	cases := []struct {
+		name string
+		exe  func(t *testing.T) *Executable
+		ctx  context.Context
+	}{
+		{
+			name: "suite limit",
+			exe: func(t *testing.T) *Executable {
+				exe := helperExecutable(t, "spin")
+				exe.CPULimit = 200 * time.Millisecond
+				exe.TlMode = scold.CPUTimeMode
+				return exe
+			},
+			ctx: context.Background(),
+		},
+		{
+			name: "test overrides the limit the suite doesn't have",
+			exe: func(t *testing.T) *Executable {
+				exe := helperExecutable(t, "spin")
+				exe.TlMode = scold.CPUTimeMode
+				return exe
+			},
+			ctx: scold.ContextWithTimeLimit(context.Background(), 200*time.Millisecond),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			exe := c.exe(t)
+
+			ctx, cancel := context.WithTimeout(c.ctx, 20*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			_, err := exe.Run(ctx, strings.NewReader(""))
+			elapsed := time.Since(start)
+
+			if err != scold.TLError {
+				t.Fatalf("got error %v, want %v", err, scold.TLError)
+			}
+
+			if elapsed > 10*time.Second {
+				t.Errorf("Run returned after %v, want the process to be killed promptly", elapsed)
+			}
+		})
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	t.Run("name with spaces and parentheses", func(t *testing.T) {
+		stat := "42 (a) b (c)) S 1 42 42 0 -1 4194560 100 0 0 0 150 50 3 4 20 0 1 0 100 1000 10"
+
+		got, ok := parseProcStat([]byte(stat))
+
+		want := procStat{pgrp: 42, cpuTime: 2070 * time.Millisecond}
+		if !ok || got != want {
+			t.Errorf("got %+v (ok %v), want %+v", got, ok, want)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, ok := parseProcStat([]byte("42 (a) S 1 42 42 0 -1 4194560 100 0 0 0 150 50"))
+
+		if ok {
+			t.Errorf("got ok, want the stat to be malformed")
+		}
+	})
+}
+
+func TestRecordUsage(t *testing.T) {
+	cmd := exec.Command("/bin/sh", "-c", "sleep 0.05")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out scold.ExecutionResult
+	recordUsage(&out, cmd.ProcessState)
+
+	if out.PeakMemory == 0 {
+		t.Errorf("got zero peak memory, want it to be recorded")
+	}
+
+	// The process gives up the CPU at least to sleep.
+	if out.VoluntaryCtxSwitches == 0 {
+		t.Errorf("got zero voluntary context switches, want them to be recorded")
+	}
+
+	if out.CPUTime() != cmd.ProcessState.UserTime()+cmd.ProcessState.SystemTime() {
+		t.Errorf("got CPU time %v, want the one of the process state", out.CPUTime())
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !linux
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/kuredoro/scold"
+)
+
+// memoryLimitSupported tells whether the memory limit can be enforced on
+// this platform.
+const memoryLimitSupported = false
+
+// watchResources does not watch anything, since there's no portable way to
+// measure the resource usage of a running process. The limits are only
+// checked after the process exits.
+func watchResources(proc *os.Process, memory scold.ByteSize, cpu time.Duration, stop <-chan struct{}) <-chan error {
+	exceeded := make(chan error, 1)
+
+	go func() {
+		<-stop
+		exceeded <- nil
+	}()
+
+	return exceeded
+}
+
+// recordUsage fills in the CPU time consumed by the exited process. The
+// memory usage and context switches are unknown.
+func recordUsage(out *scold.ExecutionResult, state *os.ProcessState) {
+	out.UserTime = state.UserTime()
+	out.SystemTime = state.SystemTime()
+}
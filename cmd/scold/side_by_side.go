@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kuredoro/scold"
+)
+
+// The separators between the columns of the side-by-side diff. Like in
+// diff -y, they tell whether the lines are the same, changed, or present
+// only in the answer or only in the output.
+const (
+	sameLinesSep   = "   "
+	changedLineSep = " | "
+	answerOnlySep  = " < "
+	outputOnlySep  = " > "
+)
+
+// wrapMarker ends the rows of a line that is continued on the next row.
+const wrapMarker = "↩"
+
+// minColumnWidth is the width of the columns if the terminal is too narrow
+// to fit them.
+const minColumnWidth = 8
+
+// richRune is a character of the side-by-side diff that may be highlighted.
+type richRune struct {
+	r           rune
+	highlighted bool
+}
+
+// sideLine is a line of the answer or of the output.
+type sideLine struct {
+	runes   []richRune
+	lexemes []string
+}
+
+func (l sideLine) highlighted() bool {
+	for _, r := range l.runes {
+		if r.highlighted {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameLines tells whether the lines shown on the same row are equal
+// according to the lexeme comparison and neither has highlighted lexemes.
+func sameLines(lx *scold.Lexer, a, b sideLine) bool {
+	return !a.highlighted() && !b.highlighted() && lx.SpansEqual(a.lexemes, b.lexemes)
+}
+
+// linePair holds the indices of the answer's and the output's lines shown
+// on the same row. If one of them is missing, its index is -1.
+type linePair struct {
+	answer, out int
+}
+
+// sideBySide renders the answer and the output in two columns that fit into
+// width characters. The lines are aligned, so that the lines that are equal
+// according to lx are on the same row, and the long lines are wrapped. The
+// characters are counted as runes, so the wide characters may shift the
+// right column.
+func sideBySide(lx *scold.Lexer, answerTitle string, answer, out []scold.RichText, width int) string {
+	colWidth := (width - len(sameLinesSep)) / 2
+	if colWidth < minColumnWidth {
+		colWidth = minColumnWidth
+	}
+
+	answerLines := splitSideLines(answer)
+	outLines := splitSideLines(out)
+
+	var str strings.Builder
+
+	fmt.Fprintf(&str, "%-*s%sOutput\n", colWidth, answerTitle, sameLinesSep)
+
+	for _, pair := range alignLines(lx, answerLines, outLines) {
+		var left, right [][]richRune
+		sep := answerOnlySep
+
+		if pair.answer != -1 {
+			left = wrapRunes(answerLines[pair.answer].runes, colWidth-1)
+		}
+
+		if pair.out != -1 {
+			right = wrapRunes(outLines[pair.out].runes, colWidth-1)
+			sep = outputOnlySep
+		}
+
+		if pair.answer != -1 && pair.out != -1 {
+			if sameLines(lx, answerLines[pair.answer], outLines[pair.out]) {
+				sep = sameLinesSep
+			} else {
+				sep = changedLineSep
+			}
+		}
+
+		rows := len(left)
+		if len(right) > rows {
+			rows = len(right)
+		}
+
+		for i := 0; i < rows; i++ {
+			writeRow(&str, left, i, colWidth, true)
+			str.WriteString(sep)
+			writeRow(&str, right, i, colWidth, false)
+			str.WriteString("\n")
+		}
+	}
+
+	return str.String()
+}
+
+// splitSideLines renders the lexemes line by line the same way DumpLexemes
+// does, except that the tabs and the non-printable characters are replaced
+// to keep the columns aligned.
+func splitSideLines(xms []scold.RichText) (lines []sideLine) {
+	var line sideLine
+	started := false
+
+	for _, xm := range xms {
+		started = true
+
+		if xm.Str == "\n" {
+			if xm.Colorful() {
+				for _, r := range scold.AltLineFeed {
+					line.runes = append(line.runes, richRune{r, true})
+				}
+			}
+
+			lines = append(lines, line)
+			line, started = sideLine{}, false
+			continue
+		}
+
+		if len(line.lexemes) != 0 {
+			line.runes = append(line.runes, richRune{' ', false})
+		}
+		line.lexemes = append(line.lexemes, xm.Str)
+
+		i := 0
+		for _, r := range xm.Str {
+			if i == len(xm.Mask) {
+				break
+			}
+
+			highlighted := xm.Mask[i]
+			switch {
+			case r == ' ' && highlighted:
+				r = []rune(scold.AltSpace)[0]
+			case r == '\t' && highlighted:
+				r = []rune(scold.AltTab)[0]
+			case r == '\t':
+				r = ' '
+			case !unicode.IsPrint(r):
+				r = unicode.ReplacementChar
+			}
+
+			line.runes = append(line.runes, richRune{r, highlighted})
+			i++
+		}
+	}
+
+	if started {
+		lines = append(lines, line)
+	}
+
+	return
+}
+
+// alignLines pairs the lines whose lexemes are equal according to lx using
+// the longest common subsequence, and the lines between them position by
+// position. If there are too many
+// lines, they are paired position by position altogether.
+func alignLines(lx *scold.Lexer, answer, out []sideLine) (pairs []linePair) {
+	n, m := len(answer), len(out)
+
+	var matches []linePair
+	if n*m <= scold.MaxAlignmentSize {
+		matches = commonLines(lx, answer, out)
+	}
+
+	ai, oi := 0, 0
+	for _, match := range append(matches, linePair{n, m}) {
+		for ; ai < match.answer && oi < match.out; ai, oi = ai+1, oi+1 {
+			pairs = append(pairs, linePair{ai, oi})
+		}
+
+		for ; ai < match.answer; ai++ {
+			pairs = append(pairs, linePair{ai, -1})
+		}
+
+		for ; oi < match.out; oi++ {
+			pairs = append(pairs, linePair{-1, oi})
+		}
+
+		if match.answer < n {
+			pairs = append(pairs, match)
+		}
+
+		ai, oi = match.answer+1, match.out+1
+	}
+
+	return
+}
+
+// commonLines finds the longest common subsequence of the lines whose
+// lexemes are equal according to lx.
+func commonLines(lx *scold.Lexer, answer, out []sideLine) (matches []linePair) {
+	n, m := len(answer), len(out)
+
+	// lengths[i*(m+1)+j] is the length of the LCS of answer[i:] and out[j:]
+	lengths := make([]int32, (n+1)*(m+1))
+	equal := make([]bool, n*m)
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			equal[i*m+j] = lx.SpansEqual(answer[i].lexemes, out[j].lexemes)
+
+			if equal[i*m+j] {
+				lengths[i*(m+1)+j] = lengths[(i+1)*(m+1)+j+1] + 1
+			} else if down, right := lengths[(i+1)*(m+1)+j], lengths[i*(m+1)+j+1]; down >= right {
+				lengths[i*(m+1)+j] = down
+			} else {
+				lengths[i*(m+1)+j] = right
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case equal[i*m+j] && lengths[i*(m+1)+j] == lengths[(i+1)*(m+1)+j+1]+1:
+			matches = append(matches, linePair{i, j})
+			i, j = i+1, j+1
+		case lengths[(i+1)*(m+1)+j] >= lengths[i*(m+1)+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return
+}
+
+// wrapRunes splits the line into rows of at most width runes. An empty line
+// is a single empty row.
+func wrapRunes(line []richRune, width int) (rows [][]richRune) {
+	for len(line) > width {
+		rows = append(rows, line[:width])
+		line = line[width:]
+	}
+
+	return append(rows, line)
+}
+
+// writeRow writes the i-th row of the wrapped line followed by the wrap
+// marker if the line continues on the next row. The rows are at most
+// width-1 runes long, so that the marker always fits. If pad is set, the
+// row is padded to width.
+func writeRow(str *strings.Builder, rows [][]richRune, i, width int, pad bool) {
+	var row []richRune
+	if i < len(rows) {
+		row = rows[i]
+	}
+
+	writeRunes(str, row)
+
+	continued := i+1 < len(rows)
+	if !pad && !continued {
+		return
+	}
+
+	str.WriteString(strings.Repeat(" ", width-1-len(row)))
+
+	if continued {
+		str.WriteString(wrapMarker)
+	} else {
+		str.WriteString(" ")
+	}
+}
+
+// writeRunes writes the runes, highlighting them with diffColor.
+func writeRunes(str *strings.Builder, row []richRune) {
+	for start := 0; start < len(row); {
+		end := start
+		var part strings.Builder
+		for end < len(row) && row[end].highlighted == row[start].highlighted {
+			part.WriteRune(row[end].r)
+			end++
+		}
+
+		if row[start].highlighted {
+			str.WriteString(scold.Au.Colorize(part.String(), diffColor).String())
+		} else {
+			str.WriteString(part.String())
+		}
+
+		start = end
+	}
+}
+
+// defaultTerminalWidth is the width of the side-by-side diff if the width of
+// the terminal cannot be determined.
+const defaultTerminalWidth = 80
+
+// terminalWidth returns the width of the terminal stdout is attached to.
+// If stdout is not a terminal, the COLUMNS environment variable is used, and
+// if it isn't set either, defaultTerminalWidth is returned.
+func terminalWidth() int {
+	if width, ok := ttyWidth(os.Stdout); ok {
+		return width
+	}
+
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+
+	return defaultTerminalWidth
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kuredoro/scold"
+	"github.com/logrusorgru/aurora"
+)
+
+// richLexemes makes unhighlighted lexemes, except for the ones prefixed
+// with '!', which are highlighted as a whole.
+func richLexemes(xms ...string) (rts []scold.RichText) {
+	for _, xm := range xms {
+		highlighted := strings.HasPrefix(xm, "!")
+		if highlighted {
+			xm = xm[1:]
+		}
+
+		mask := make([]bool, len([]rune(xm)))
+		for i := range mask {
+			mask[i] = highlighted
+		}
+
+		rts = append(rts, scold.RichText{Str: xm, Mask: mask})
+	}
+
+	return
+}
+
+func TestSideBySide(t *testing.T) {
+	defer func(au aurora.Aurora) { scold.Au = au }(scold.Au)
+	scold.Au = aurora.NewAurora(false)
+
+	lx := &scold.Lexer{Precision: 2}
+
+	cases := []struct {
+		name   string
+		answer []scold.RichText
+		out    []scold.RichText
+		width  int
+		want   string
+	}{
+		{
+			name:   "same lines",
+			answer: richLexemes("1", "2", "\n", "3"),
+			out:    richLexemes("1", "2", "\n", "3"),
+			width:  23,
+			want: "" +
+				"Answer       Output\n" +
+				"1 2          1 2\n" +
+				"3            3\n",
+		},
+		{
+			name:   "changed and missing lines",
+			answer: richLexemes("1", "!2", "\n", "a", "\n", "!b", "\n", "c"),
+			out:    richLexemes("1", "!3", "\n", "a", "\n", "c", "\n", "!d"),
+			width:  23,
+			want: "" +
+				"Answer       Output\n" +
+				"1 2        | 1 3\n" +
+				"a            a\n" +
+				"b          < \n" +
+				"c            c\n" +
+				"           > d\n",
+		},
+		{
+			name:   "highlighted whitespace",
+			answer: richLexemes("a", "!\n", "b"),
+			out:    richLexemes("a", "b"),
+			width:  23,
+			want: "" +
+				"Answer       Output\n" +
+				"a" + scold.AltLineFeed + "        | a b\n" +
+				"b          < \n",
+		},
+		{
+			name:   "lexically equal lines",
+			answer: richLexemes("!extra", "\n", "1.0", "2"),
+			out:    richLexemes("1", "2.00"),
+			width:  23,
+			want: "" +
+				"Answer       Output\n" +
+				"extra      < \n" +
+				"1.0 2        1 2.00\n",
+		},
+		{
+			name:   "wrapped lines",
+			answer: richLexemes("!abcdefghijklmnop"),
+			out:    richLexemes("!abcdefghijklmnoq"),
+			width:  23,
+			want: "" +
+				"Answer       Output\n" +
+				"abcdefghi" + wrapMarker + " | abcdefghi" + wrapMarker + "\n" +
+				"jklmnop    | jklmnoq\n",
+		},
+		{
+			name:   "narrow terminal",
+			answer: richLexemes("abcdefghij"),
+			out:    richLexemes("abcdefghij"),
+			width:  5,
+			want: "" +
+				"Answer     Output\n" +
+				"abcdefg" + wrapMarker + "   abcdefg" + wrapMarker + "\n" +
+				"hij        hij\n",
+		},
+		{
+			name:   "empty output",
+			answer: richLexemes("1"),
+			out:    nil,
+			width:  23,
+			want: "" +
+				"Answer       Output\n" +
+				"1          < \n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := sideBySide(lx, "Answer", c.answer, c.out, c.width)
+
+			if got != c.want {
+				t.Errorf("got\n%s\nwant\n%s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// ttyWidth returns the number of columns of the console window the file is
+// attached to.
+func ttyWidth(f *os.File) (int, bool) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(f.Fd()), &info); err != nil {
+		return 0, false
+	}
+
+	width := int(info.Window.Right-info.Window.Left) + 1
+	if width <= 0 {
+		return 0, false
+	}
+
+	return width, true
+}
@@ -3,17 +3,42 @@ package scold
 import (
 	"github.com/logrusorgru/aurora"
 	"strings"
+	"unicode/utf8"
 )
 
 // AltLineFeed is the representation of LF in textual form, that replaces LF
 // when it's colorized.
 const AltLineFeed = "\\n"
 
+// AltSpace and AltTab are the visible representations of space and tab
+// characters, that replace them when they're colorized.
+const (
+	AltSpace = "·"
+	AltTab   = "→"
+)
+
 // DumpLexemes is used to transform array of possibly colorized lexemes into a
 // human readable format. The lexemes are separated by spaces. There are no
 // trailing spaces. Colorized newlines are replaced by printable AltLineFeed
-// string + a newline.
+// string + a newline. Colorized spaces and tabs inside the lexemes are
+// replaced by AltSpace and AltTab.
 func DumpLexemes(xms []RichText, color aurora.Color) string {
+	return dumpLexemes(xms, func(xm RichText) string {
+		return xm.Colorize(color)
+	})
+}
+
+// DumpLexemesHTML is the same as DumpLexemes, except that the result is
+// escaped for embedding into HTML, and the colorized parts are wrapped in
+// span elements of the given class. The result is meant to be put inside
+// a pre element.
+func DumpLexemesHTML(xms []RichText, class string) string {
+	return dumpLexemes(xms, func(xm RichText) string {
+		return xm.HTML(class)
+	})
+}
+
+func dumpLexemes(xms []RichText, colorize func(RichText) string) string {
 	var str strings.Builder
 
 	x := 0
@@ -26,16 +51,45 @@ func DumpLexemes(xms []RichText, color aurora.Color) string {
 			x = -1
 
 			if xm.Colorful() {
-				str.WriteString(Au.Colorize(AltLineFeed, color).String())
+				str.WriteString(colorize(RichText{AltLineFeed, []bool{true, true}}))
 				str.WriteRune('\n')
 				x++
 				continue
 			}
 		}
 
-		str.WriteString(xm.Colorize(color))
+		str.WriteString(colorize(showWhitespace(xm)))
 		x++
 	}
 
 	return str.String()
 }
+
+// showWhitespace replaces the colorized spaces and tabs of the lexeme with
+// their visible representations. The mask stays valid, since each rune is
+// replaced by a single rune.
+func showWhitespace(xm RichText) RichText {
+	if !strings.ContainsAny(xm.Str, " \t") || !xm.Colorful() {
+		return xm
+	}
+
+	var str strings.Builder
+
+	for i, pos := 0, 0; pos < len(xm.Str); i++ {
+		r, width := utf8.DecodeRuneInString(xm.Str[pos:])
+		colorized := i < len(xm.Mask) && xm.Mask[i]
+
+		switch {
+		case colorized && r == ' ':
+			str.WriteString(AltSpace)
+		case colorized && r == '\t':
+			str.WriteString(AltTab)
+		default:
+			str.WriteString(xm.Str[pos : pos+width])
+		}
+
+		pos += width
+	}
+
+	return RichText{str.String(), xm.Mask}
+}
@@ -77,4 +77,45 @@ func TestDumpLexemes(t *testing.T) {
 
 		scold.AssertText(t, got, want)
 	})
+
+	t.Run("multibyte lexemes compared against each other", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+
+		got, _ := lexer.Compare(lexer.Scan("привет 世界\n"), lexer.Scan("привед 世\n"))
+
+		want := "приве" + aurora.Bold("т").String() + " 世" + aurora.Bold("界").String() + "\n"
+
+		scold.AssertText(t, scold.DumpLexemes(got, aurora.BoldFm), want)
+	})
+
+	t.Run("colorized whitespace is visible", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareExact}
+
+		got, _ := lexer.Compare(lexer.Scan("a\t b  \n"), lexer.Scan("a  b\n"))
+
+		want := "a" + aurora.Bold("→").String() + " b" + aurora.Bold("··").String() + "\n"
+
+		scold.AssertText(t, scold.DumpLexemes(got, aurora.BoldFm), want)
+	})
+
+	t.Run("plain whitespace is kept", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines}
+
+		got, _ := lexer.Compare(lexer.Scan("a\tb c\n"), lexer.Scan("a\tb d\n"))
+
+		want := "a\tb " + aurora.Bold("c").String()
+
+		scold.AssertText(t, scold.DumpLexemes(got, aurora.BoldFm), want)
+	})
+}
+
+func TestDumpLexemesHTML(t *testing.T) {
+	lexer := &scold.Lexer{Mode: scold.CompareExact}
+
+	got, _ := lexer.Compare(lexer.Scan("a<b \n1\n\n"), lexer.Scan("a<b\n2\n"))
+
+	want := `a&lt;b<span class="hl">·</span>` + "\n" + `<span class="hl">1</span>` + "\n" +
+		`<span class="hl">\n</span>` + "\n"
+
+	scold.AssertText(t, scold.DumpLexemesHTML(got, "hl"), want)
 }
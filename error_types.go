@@ -168,3 +168,20 @@ func (e *NotTextUnmarshalableTypeError) Error() string {
 func (e *NotTextUnmarshalableTypeError) Equal(other *NotTextUnmarshalableTypeError) bool {
 	return e.Field == other.Field && e.Type == other.Type && e.TypeName == other.TypeName
 }
+
+// FileError maps a file, whose path is stored in Path, to a particular
+// error Err.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// Error renders the underlying error preceeded with the file's path.
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap makes FileError usable with built-in errors package.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
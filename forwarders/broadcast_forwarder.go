@@ -0,0 +1,28 @@
+package forwarders
+
+import "github.com/kuredoro/scold"
+
+// BroadcastForwarder forwards each testing event to all of its receivers
+// in order. It can be used to report the testing in several ways at once.
+type BroadcastForwarder []scold.TestingEventListener
+
+// TestStarted forwards the event to the receivers.
+func (f BroadcastForwarder) TestStarted(id int) {
+	for _, receiver := range f {
+		receiver.TestStarted(id)
+	}
+}
+
+// TestFinished forwards the event to the receivers.
+func (f BroadcastForwarder) TestFinished(test *scold.Test, result *scold.TestResult) {
+	for _, receiver := range f {
+		receiver.TestFinished(test, result)
+	}
+}
+
+// SuiteFinished forwards the event to the receivers.
+func (f BroadcastForwarder) SuiteFinished(b *scold.TestingBatch) {
+	for _, receiver := range f {
+		receiver.SuiteFinished(b)
+	}
+}
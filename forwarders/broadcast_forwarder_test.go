@@ -0,0 +1,33 @@
+package forwarders_test
+
+import (
+	"testing"
+
+	"github.com/kuredoro/scold"
+	"github.com/kuredoro/scold/forwarders"
+)
+
+func TestBroadcastForwarder(t *testing.T) {
+	spies := []*scold.SpyPrinter{{}, {}}
+	forwarder := forwarders.BroadcastForwarder{spies[0], spies[1]}
+
+	test := &scold.Test{Input: "1", Output: "1"}
+
+	forwarder.TestStarted(1)
+	forwarder.TestFinished(test, testResultWithID(1))
+	forwarder.SuiteFinished(nil)
+
+	for i, spy := range spies {
+		if len(spy.StartedIDs) != 1 || spy.StartedIDs[0] != 1 {
+			t.Errorf("receiver #%d got started IDs %v, want [1]", i, spy.StartedIDs)
+		}
+
+		if len(spy.FinishedTests) != 1 || spy.FinishedTests[0] != test {
+			t.Errorf("receiver #%d got finished tests %v, want [%v]", i, spy.FinishedTests, test)
+		}
+
+		if !spy.Finished {
+			t.Errorf("receiver #%d didn't get the suite finished event", i)
+		}
+	}
+}
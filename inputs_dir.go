@@ -0,0 +1,246 @@
+package scold
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kuredoro/scold/util"
+)
+
+// A set of errors that may be produced while loading the tests from
+// a directory.
+const (
+	NoTestFiles       = StringError("no test files found")
+	AnswerFileMissing = StringWarning("answer file is missing, skipping")
+	InputFileMissing  = StringWarning("input file is missing, skipping")
+
+	// ErrTestFilePatternBadSyntax is issued when TestFilePattern is
+	// unmarshalled with a value that doesn't contain two globs separated by
+	// a colon, each having exactly one '*'.
+	ErrTestFilePatternBadSyntax = StringError("bad syntax. Correct values could be \"*.in:*.ans\" or \"*:*.a\"")
+)
+
+// DirConfigFile is the name of the file in the directory with the tests
+// that holds the suite's options. Its syntax is the same as of the config
+// at the beginning of the inputs file.
+const DirConfigFile = "config.txt"
+
+// TestFilePattern describes how the input and the answer files of a test are
+// named. Both are globs with exactly one '*' that stands for the name of the
+// test, so that the files "01.in" and "01.ans" are paired by the pattern
+// {"*.in", "*.ans"}. Implements encoding.TextUnmarshaler.
+type TestFilePattern struct {
+	Input  string
+	Answer string
+}
+
+// DefaultTestFilePatterns are the naming conventions used by the popular
+// problem archives and Polygon.
+var DefaultTestFilePatterns = []TestFilePattern{
+	{"*.in", "*.ans"},
+	{"*.in", "*.out"},
+	{"*", "*.a"},
+}
+
+// UnmarshalText accepts the input and the answer globs separated by a colon,
+// like "*.in:*.ans".
+func (p *TestFilePattern) UnmarshalText(b []byte) error {
+	parts := strings.Split(string(b), ":")
+	if len(parts) != 2 {
+		return ErrTestFilePatternBadSyntax
+	}
+
+	for _, glob := range parts {
+		if strings.Count(glob, "*") != 1 {
+			return ErrTestFilePatternBadSyntax
+		}
+	}
+
+	*p = TestFilePattern{parts[0], parts[1]}
+	return nil
+}
+
+func (p TestFilePattern) String() string {
+	return p.Input + ":" + p.Answer
+}
+
+// matchGlob returns the part of the name that matched the '*' in glob.
+func matchGlob(glob, name string) (string, bool) {
+	prefix, suffix, _ := strings.Cut(glob, "*")
+
+	if len(name) <= len(prefix)+len(suffix) ||
+		!strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return "", false
+	}
+
+	return name[len(prefix) : len(name)-len(suffix)], true
+}
+
+// testNameShape replaces each run of digits in the test name with a single
+// zero, so that the names of the numbered tests, like "01" and "sample2",
+// would have the same shape as their siblings.
+func testNameShape(test string) string {
+	var shape strings.Builder
+
+	digits := false
+	for _, r := range test {
+		if unicode.IsDigit(r) {
+			if !digits {
+				shape.WriteByte('0')
+			}
+		} else {
+			shape.WriteRune(r)
+		}
+
+		digits = unicode.IsDigit(r)
+	}
+
+	return shape.String()
+}
+
+// testFiles maps the test names to the input and the answer files.
+type testFiles struct {
+	pattern TestFilePattern
+	inputs  map[string]string
+	answers map[string]string
+	pairs   int
+}
+
+// looksLikeTest tells whether the unpaired file matched by glob is worth
+// a warning. The glob "*" matches any file, like a README or a compiled
+// solution, so such files are reported only if the test's name has the same
+// shape as one of the paired tests.
+func looksLikeTest(glob, test string, shapes map[string]bool) bool {
+	return glob != "*" || shapes[testNameShape(test)]
+}
+
+func matchTestFiles(names []string, pattern TestFilePattern) testFiles {
+	files := testFiles{
+		pattern: pattern,
+		inputs:  make(map[string]string),
+		answers: make(map[string]string),
+	}
+
+	for _, name := range names {
+		// If the input glob is broader than the answer one, like "*" and
+		// "*.a", the answers would be mistaken for inputs.
+		if test, ok := matchGlob(pattern.Answer, name); ok {
+			files.answers[test] = name
+		} else if test, ok := matchGlob(pattern.Input, name); ok {
+			files.inputs[test] = name
+		}
+	}
+
+	for test := range files.inputs {
+		if _, ok := files.answers[test]; ok {
+			files.pairs++
+		}
+	}
+
+	return files
+}
+
+// LoadInputsDir reads the tests stored in the root of the file system as
+// separate files, one for the input and one for the answer, as it is
+// customary for problem archives. The files are paired according to the
+// pattern that matches the most of them. The tests are ordered by their
+// names naturally, i.e., "2.in" goes before "10.in".
+//
+// The files that could not be paired are skipped with a FileError carrying
+// a warning, unless they don't look like the test files. The suite's options
+// are read from DirConfigFile, if it exists, and default to
+// DefaultInputsConfig. The errors in it are wrapped in FileError too.
+func LoadInputsDir(fsys fs.FS, patterns []TestFilePattern) (inputs Inputs, errs []error) {
+	inputs.Config = DefaultInputsConfig
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return inputs, []error{err}
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && entry.Name() != DirConfigFile {
+			names = append(names, entry.Name())
+		}
+	}
+
+	config, err := fs.ReadFile(fsys, DirConfigFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		errs = append(errs, &FileError{DirConfigFile, err})
+	}
+
+	for _, err := range scanSuiteConfig(string(config), &inputs.Config) {
+		errs = append(errs, &FileError{DirConfigFile, err})
+	}
+
+	var best testFiles
+	for _, pattern := range patterns {
+		files := matchTestFiles(names, pattern)
+		if files.pairs > best.pairs {
+			best = files
+		}
+	}
+
+	if best.pairs == 0 {
+		return inputs, append(errs, NoTestFiles)
+	}
+
+	var tests []string
+	shapes := make(map[string]bool)
+	for test := range best.inputs {
+		if _, ok := best.answers[test]; ok {
+			tests = append(tests, test)
+			shapes[testNameShape(test)] = true
+		}
+	}
+
+	var warnings []error
+	for test, name := range best.inputs {
+		_, ok := best.answers[test]
+		if !ok && looksLikeTest(best.pattern.Input, test, shapes) {
+			warnings = append(warnings, &FileError{name, AnswerFileMissing})
+		}
+	}
+
+	for test, name := range best.answers {
+		_, ok := best.inputs[test]
+		if !ok && looksLikeTest(best.pattern.Answer, test, shapes) {
+			warnings = append(warnings, &FileError{name, InputFileMissing})
+		}
+	}
+
+	sort.Slice(tests, func(i, j int) bool {
+		return util.NaturalLess(tests[i], tests[j])
+	})
+
+	sort.Slice(warnings, func(i, j int) bool {
+		return util.NaturalLess(warnings[i].(*FileError).Path, warnings[j].(*FileError).Path)
+	})
+
+	errs = append(errs, warnings...)
+
+	for _, test := range tests {
+		input, err := fs.ReadFile(fsys, best.inputs[test])
+		if err != nil {
+			errs = append(errs, &FileError{best.inputs[test], err})
+			continue
+		}
+
+		answer, err := fs.ReadFile(fsys, best.answers[test])
+		if err != nil {
+			errs = append(errs, &FileError{best.answers[test], err})
+			continue
+		}
+
+		inputs.Tests = append(inputs.Tests, Test{
+			Input:  string(input),
+			Output: string(answer),
+		})
+	}
+
+	return inputs, errs
+}
@@ -0,0 +1,208 @@
+package scold_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kuredoro/scold"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func file(text string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(text)}
+}
+
+func TestLoadInputsDir(t *testing.T) {
+	t.Run("inputs and answers are paired and sorted naturally",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"10.in":  file("10\n"),
+				"10.ans": file("100\n"),
+				"2.in":   file("2\n"),
+				"2.ans":  file("4\n"),
+				"1.in":   file("1\n"),
+				"1.ans":  file("1\n"),
+			}
+
+			testsWant := []scold.Test{
+				{Input: "1\n", Output: "1\n"},
+				{Input: "2\n", Output: "4\n"},
+				{Input: "10\n", Output: "100\n"},
+			}
+
+			inputs, errs := scold.LoadInputsDir(fsys, scold.DefaultTestFilePatterns)
+
+			scold.AssertNoErrors(t, errs)
+			scold.AssertTests(t, inputs.Tests, testsWant)
+			scold.AssertDefaultConfig(t, inputs.Config)
+		})
+
+	t.Run("the pattern matching the most files is chosen",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"01":       file("a\n"),
+				"01.a":     file("A\n"),
+				"02":       file("b\n"),
+				"02.a":     file("B\n"),
+				"tests.in": file("stray\n"),
+			}
+
+			testsWant := []scold.Test{
+				{Input: "a\n", Output: "A\n"},
+				{Input: "b\n", Output: "B\n"},
+			}
+
+			inputs, errs := scold.LoadInputsDir(fsys, scold.DefaultTestFilePatterns)
+
+			scold.AssertNoErrors(t, errs)
+			scold.AssertTests(t, inputs.Tests, testsWant)
+		})
+
+	t.Run("only the files resembling tests are warned about with catch-all pattern",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"01":        file("a\n"),
+				"01.a":      file("A\n"),
+				"02":        file("b\n"),
+				"02.a":      file("B\n"),
+				"03":        file("c\n"),
+				"04.a":      file("D\n"),
+				"README.md": file("hi\n"),
+				"solution":  file("\x7fELF"),
+			}
+
+			testsWant := []scold.Test{
+				{Input: "a\n", Output: "A\n"},
+				{Input: "b\n", Output: "B\n"},
+			}
+
+			inputs, errs := scold.LoadInputsDir(fsys, scold.DefaultTestFilePatterns)
+
+			td.Cmp(t, errs, []error{
+				&scold.FileError{"03", scold.AnswerFileMissing},
+				&scold.FileError{"04.a", scold.InputFileMissing},
+			})
+			scold.AssertTests(t, inputs.Tests, testsWant)
+		})
+
+	t.Run("suite config is read from the config file",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				scold.DirConfigFile: file("tl = 2s\nprec = 3\n"),
+				"1.in":              file("1\n"),
+				"1.ans":             file("1\n"),
+			}
+
+			configWant := scold.DefaultInputsConfig
+			configWant.Tl = scold.NewPositiveDuration(2 * time.Second)
+			configWant.Prec = 3
+
+			inputs, errs := scold.LoadInputsDir(fsys, scold.DefaultTestFilePatterns)
+
+			scold.AssertNoErrors(t, errs)
+			scold.AssertTests(t, inputs.Tests, []scold.Test{{Input: "1\n", Output: "1\n"}})
+			td.Cmp(t, inputs.Config, configWant)
+		})
+
+	t.Run("config file errors are reported with the file's name",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				scold.DirConfigFile: file("tl = 2s\n= 3\nfoo = bar\n"),
+				"1.in":              file("1\n"),
+				"1.ans":             file("1\n"),
+			}
+
+			inputs, errs := scold.LoadInputsDir(fsys, scold.DefaultTestFilePatterns)
+
+			td.Cmp(t, errs, []error{
+				&scold.FileError{scold.DirConfigFile, &scold.LineRangeError{2, []string{"= 3"}, scold.KeyMissing}},
+				&scold.FileError{scold.DirConfigFile, &scold.LineRangeError{3, []string{"foo = bar"}, &scold.FieldError{"foo", scold.ErrUnknownField}}},
+			})
+			td.Cmp(t, inputs.Config.Tl, scold.NewPositiveDuration(2*time.Second))
+		})
+
+	t.Run("unpaired files are skipped with warnings",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"1.in":  file("1\n"),
+				"1.out": file("1\n"),
+				"3.in":  file("3\n"),
+				"4.out": file("4\n"),
+				"sub":   &fstest.MapFile{Mode: fs.ModeDir},
+			}
+
+			testsWant := []scold.Test{
+				{Input: "1\n", Output: "1\n"},
+			}
+
+			inputs, errs := scold.LoadInputsDir(fsys, scold.DefaultTestFilePatterns)
+
+			td.Cmp(t, errs, []error{
+				&scold.FileError{"3.in", scold.AnswerFileMissing},
+				&scold.FileError{"4.out", scold.InputFileMissing},
+			})
+			scold.AssertTests(t, inputs.Tests, testsWant)
+		})
+
+	t.Run("custom patterns",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"input1.txt":  file("1\n"),
+				"output1.txt": file("1\n"),
+				"1.in":        file("x\n"),
+				"1.ans":       file("y\n"),
+			}
+
+			patterns := []scold.TestFilePattern{{"input*.txt", "output*.txt"}}
+
+			testsWant := []scold.Test{
+				{Input: "1\n", Output: "1\n"},
+			}
+
+			inputs, errs := scold.LoadInputsDir(fsys, patterns)
+
+			scold.AssertNoErrors(t, errs)
+			scold.AssertTests(t, inputs.Tests, testsWant)
+		})
+
+	t.Run("no tests is an error",
+		func(t *testing.T) {
+			fsys := fstest.MapFS{
+				"README.md": file("hi\n"),
+			}
+
+			inputs, errs := scold.LoadInputsDir(fsys, []scold.TestFilePattern{{"*.in", "*.ans"}})
+
+			td.Cmp(t, errs, []error{scold.NoTestFiles})
+			scold.AssertTests(t, inputs.Tests, nil)
+		})
+}
+
+func TestTestFilePattern(t *testing.T) {
+	t.Run("input and answer globs",
+		func(t *testing.T) {
+			var pattern scold.TestFilePattern
+			err := pattern.UnmarshalText([]byte("*.in:*.ans"))
+
+			td.CmpNoError(t, err)
+			td.Cmp(t, pattern, scold.TestFilePattern{"*.in", "*.ans"})
+		})
+
+	t.Run("missing colon is forbidden",
+		func(t *testing.T) {
+			var pattern scold.TestFilePattern
+			err := pattern.UnmarshalText([]byte("*.in"))
+
+			td.Cmp(t, err, scold.ErrTestFilePatternBadSyntax)
+		})
+
+	t.Run("globs must have exactly one asterisk",
+		func(t *testing.T) {
+			var pattern scold.TestFilePattern
+
+			td.Cmp(t, pattern.UnmarshalText([]byte(".in:*.ans")), scold.ErrTestFilePatternBadSyntax)
+			td.Cmp(t, pattern.UnmarshalText([]byte("**.in:*.ans")), scold.ErrTestFilePatternBadSyntax)
+		})
+}
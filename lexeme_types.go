@@ -0,0 +1,200 @@
+package scold
+
+import "fmt"
+
+// The errors returned by Lexer.RegisterType.
+const (
+	ErrLexemeTypeNameMissing    = StringError("lexeme type must have a name")
+	ErrLexemeTypeExists         = StringError("lexeme type with this name is already registered")
+	ErrLexemeTypeParentUnknown  = StringError("parent lexeme type is not registered")
+	ErrLexemeTypeMatchMissing   = StringError("lexeme type must have a matcher")
+	ErrLexemeTypeCompareMissing = StringError("lexeme type must have a comparator or a mask generator")
+)
+
+// The names of the builtin lexeme types.
+const (
+	StringType          = "string"
+	ScientificFloatType = "scifloat"
+	FloatType           = "float"
+	IntType             = "int"
+)
+
+// LexemeType describes a kind of lexemes that are compared in a special
+// way, like integers or floats.
+//
+// The types form a specialization tree rooted at the string type. A type T
+// is a specialization of a type U if any value of type T is of type U also.
+// For example, 42 is a float and is an int, but 42.2 is a float but not an
+// int. Hence, int is a specialization of float, and float is its parent.
+// Two lexemes are compared as the values of their common type, the most
+// specialized type both of them are of. Since the types form a tree, it's
+// possible to have types unrelated to each other. Imagine a lexeme type
+// 'hash' that classifies strings of form 2400f9b. The float is not
+// a specialization of hash, because 42.2 is not a hash, and likewise the
+// hash is not a specialization of float, because 2400f9b is not a float.
+// A hash and a float are then compared as strings.
+type LexemeType struct {
+	Name string
+
+	// Parent is the name of the type this type specializes. It must be
+	// registered beforehand. Any lexeme that matches this type must match
+	// the parent too.
+	Parent string
+
+	// Match tells whether the lexeme is of this type.
+	Match func(xm string) bool
+
+	// Equal tells whether two lexemes of this type are equal. If nil, they
+	// are equal if GenMask highlights nothing.
+	Equal func(l *Lexer, a, b string) bool
+
+	// GenMask highlights the parts of target that differ from source. If
+	// nil, the whole target is highlighted when the lexemes are not Equal.
+	GenMask func(l *Lexer, target, source string) []bool
+}
+
+// lexemeTypes is the specialization tree of lexeme types.
+type lexemeTypes struct {
+	byName map[string]*LexemeType
+
+	// children lists the direct specializations of each type in the order
+	// of their registration.
+	children map[string][]*LexemeType
+}
+
+// builtinLexemeTypes are used by the lexers with no custom types registered.
+var builtinLexemeTypes = newBuiltinLexemeTypes()
+
+func newBuiltinLexemeTypes() *lexemeTypes {
+	types := &lexemeTypes{
+		byName: map[string]*LexemeType{
+			StringType: {
+				Name:    StringType,
+				Match:   func(string) bool { return true },
+				GenMask: (*Lexer).GenMaskForString,
+			},
+		},
+		children: make(map[string][]*LexemeType),
+	}
+
+	builtins := []LexemeType{
+		{
+			Name:    ScientificFloatType,
+			Parent:  StringType,
+			Match:   IsScientificFloatLexeme,
+			GenMask: (*Lexer).GenMaskForScientificFloat,
+		},
+		{
+			Name:    FloatType,
+			Parent:  ScientificFloatType,
+			Match:   IsFloatLexeme,
+			GenMask: (*Lexer).GenMaskForFloat,
+		},
+		{
+			Name:    IntType,
+			Parent:  FloatType,
+			Match:   IsIntLexeme,
+			GenMask: (*Lexer).GenMaskForInt,
+		},
+	}
+
+	for _, t := range builtins {
+		if err := types.add(t); err != nil {
+			panic(err)
+		}
+	}
+
+	return types
+}
+
+func (ts *lexemeTypes) clone() *lexemeTypes {
+	copy := &lexemeTypes{
+		byName:   make(map[string]*LexemeType, len(ts.byName)),
+		children: make(map[string][]*LexemeType, len(ts.children)),
+	}
+
+	for name, t := range ts.byName {
+		copy.byName[name] = t
+	}
+
+	for name, children := range ts.children {
+		copy.children[name] = append([]*LexemeType(nil), children...)
+	}
+
+	return copy
+}
+
+func (ts *lexemeTypes) add(t LexemeType) error {
+	if t.Name == "" {
+		return ErrLexemeTypeNameMissing
+	}
+
+	var err error
+	switch {
+	case ts.byName[t.Name] != nil:
+		err = ErrLexemeTypeExists
+	case ts.byName[t.Parent] == nil:
+		err = ErrLexemeTypeParentUnknown
+	case t.Match == nil:
+		err = ErrLexemeTypeMatchMissing
+	case t.Equal == nil && t.GenMask == nil:
+		err = ErrLexemeTypeCompareMissing
+	}
+
+	if err != nil {
+		return fmt.Errorf("lexeme type %q: %w", t.Name, err)
+	}
+
+	ts.byName[t.Name] = &t
+	ts.children[t.Parent] = append(ts.children[t.Parent], &t)
+
+	return nil
+}
+
+// typeOf descends the specialization tree from the root as long as the
+// lexeme matches one of the children. If several children match, the one
+// registered first wins.
+func (ts *lexemeTypes) typeOf(xm string) *LexemeType {
+	t := ts.byName[StringType]
+
+descend:
+	for {
+		for _, child := range ts.children[t.Name] {
+			if child.Match(xm) {
+				t = child
+				continue descend
+			}
+		}
+
+		return t
+	}
+}
+
+// common returns the lowest common ancestor of the two types. It's called
+// for each pair of the compared lexemes, so it doesn't allocate.
+func (ts *lexemeTypes) common(a, b *LexemeType) *LexemeType {
+	aDepth, bDepth := ts.depth(a), ts.depth(b)
+
+	for ; aDepth > bDepth; aDepth-- {
+		a = ts.byName[a.Parent]
+	}
+
+	for ; bDepth > aDepth; bDepth-- {
+		b = ts.byName[b.Parent]
+	}
+
+	for a != b {
+		a, b = ts.byName[a.Parent], ts.byName[b.Parent]
+	}
+
+	return a
+}
+
+// depth returns the number of ancestors of the type.
+func (ts *lexemeTypes) depth(t *LexemeType) (depth int) {
+	for ; t.Parent != ""; t = ts.byName[t.Parent] {
+		depth++
+	}
+
+	return
+}
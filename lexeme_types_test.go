@@ -0,0 +1,199 @@
+package scold_test
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kuredoro/scold"
+)
+
+var fractionType = scold.LexemeType{
+	Name:   "fraction",
+	Parent: scold.StringType,
+	Match: func(xm string) bool {
+		_, ok := new(big.Rat).SetString(xm)
+		return ok && strings.Count(xm, "/") == 1
+	},
+	Equal: func(l *scold.Lexer, a, b string) bool {
+		aVal, _ := new(big.Rat).SetString(a)
+		bVal, _ := new(big.Rat).SetString(b)
+		return aVal.Cmp(bVal) == 0
+	},
+}
+
+var booleanType = scold.LexemeType{
+	Name:   "boolean",
+	Parent: scold.StringType,
+	Match: func(xm string) bool {
+		return strings.EqualFold(xm, "yes") || strings.EqualFold(xm, "no")
+	},
+	Equal: func(l *scold.Lexer, a, b string) bool {
+		return strings.EqualFold(a, b)
+	},
+}
+
+var hashType = scold.LexemeType{
+	Name:    "hash",
+	Parent:  scold.StringType,
+	Match:   regexp.MustCompile(`^[0-9a-f]{7}$`).MatchString,
+	GenMask: (*scold.Lexer).GenMaskForString,
+}
+
+func TestLexerTypeOf(t *testing.T) {
+	lexer := &scold.Lexer{}
+
+	cases := []struct {
+		Lexeme string
+		Want   string
+	}{
+		{"foo", scold.StringType},
+		{"1e-9", scold.ScientificFloatType},
+		{"inf", scold.ScientificFloatType},
+		{"1.5", scold.FloatType},
+		{"42", scold.IntType},
+		{"-" + strings.Repeat("9", 100), scold.IntType},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Lexeme, func(t *testing.T) {
+			got := lexer.TypeOf(test.Lexeme)
+
+			if got != test.Want {
+				t.Errorf("got type %q, want %q", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestLexerRegisterType(t *testing.T) {
+	t.Run("custom types are compared with their comparators", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+
+		for _, lt := range []scold.LexemeType{fractionType, booleanType} {
+			if err := lexer.RegisterType(lt); err != nil {
+				t.Fatalf("got error %v, want nil", err)
+			}
+		}
+
+		target := []string{"1/2", "YES", "no", "2/3", "1/2"}
+		source := []string{"2/4", "yes", "No", "3/4", "0.5"}
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{target[0], []bool{false, false, false}},
+			{target[1], []bool{false, false, false}},
+			{target[2], []bool{false, false}},
+			{target[3], []bool{true, true, true}},
+			{target[4], lexer.GenMaskForString(target[4], source[4])},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("common type is the lowest common ancestor", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+
+		err := lexer.RegisterType(hashType)
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+
+		cases := []struct {
+			A, B string
+			Want string
+		}{
+			{"2400f9b", "2400f9c", "hash"},
+			{"2400f9b", "1234567", scold.StringType},
+			{"1234567", "1.5", scold.FloatType},
+			{"1234567", "1e9", scold.ScientificFloatType},
+			{"1234567", "7654321", scold.IntType},
+		}
+
+		for _, test := range cases {
+			got := lexer.CommonType(test.A, test.B)
+
+			if got != test.Want {
+				t.Errorf("got common type of %s and %s %q, want %q", test.A, test.B, got, test.Want)
+			}
+		}
+	})
+
+	t.Run("types can specialize custom types", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+
+		_ = lexer.RegisterType(booleanType)
+		err := lexer.RegisterType(scold.LexemeType{
+			Name:   "upper boolean",
+			Parent: "boolean",
+			Match: func(xm string) bool {
+				return xm == "YES" || xm == "NO"
+			},
+			Equal: func(l *scold.Lexer, a, b string) bool {
+				return a == b
+			},
+		})
+		if err != nil {
+			t.Fatalf("got error %v, want nil", err)
+		}
+
+		if got := lexer.CommonType("YES", "NO"); got != "upper boolean" {
+			t.Errorf("got common type %q, want %q", got, "upper boolean")
+		}
+
+		if got := lexer.CommonType("YES", "no"); got != "boolean" {
+			t.Errorf("got common type %q, want %q", got, "boolean")
+		}
+	})
+
+	t.Run("copies of the lexer are not affected", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+		copy := *lexer
+
+		_ = lexer.RegisterType(booleanType)
+
+		if got := copy.TypeOf("yes"); got != scold.StringType {
+			t.Errorf("got type %q in the copy, want %q", got, scold.StringType)
+		}
+
+		if got := (&scold.Lexer{}).TypeOf("yes"); got != scold.StringType {
+			t.Errorf("got type %q in a new lexer, want %q", got, scold.StringType)
+		}
+	})
+
+	t.Run("invalid types are rejected", func(t *testing.T) {
+		match := func(string) bool { return true }
+
+		cases := []struct {
+			Title string
+			Type  scold.LexemeType
+			Want  error
+		}{
+			{"no name", scold.LexemeType{Parent: scold.StringType, Match: match, GenMask: hashType.GenMask}, scold.ErrLexemeTypeNameMissing},
+			{"taken name", scold.LexemeType{Name: scold.IntType, Parent: scold.StringType, Match: match, GenMask: hashType.GenMask}, scold.ErrLexemeTypeExists},
+			{"unknown parent", scold.LexemeType{Name: "x", Parent: "y", Match: match, GenMask: hashType.GenMask}, scold.ErrLexemeTypeParentUnknown},
+			{"no parent", scold.LexemeType{Name: "x", Match: match, GenMask: hashType.GenMask}, scold.ErrLexemeTypeParentUnknown},
+			{"no matcher", scold.LexemeType{Name: "x", Parent: scold.StringType, GenMask: hashType.GenMask}, scold.ErrLexemeTypeMatchMissing},
+			{"no comparator", scold.LexemeType{Name: "x", Parent: scold.StringType, Match: match}, scold.ErrLexemeTypeCompareMissing},
+		}
+
+		for _, test := range cases {
+			t.Run(test.Title, func(t *testing.T) {
+				lexer := &scold.Lexer{}
+
+				err := lexer.RegisterType(test.Type)
+				if !errors.Is(err, test.Want) {
+					t.Errorf("got error %v, want %v", err, test.Want)
+				}
+
+				if got := lexer.TypeOf("foo"); got != scold.StringType {
+					t.Errorf("got type %q after failed registration, want %q", got, scold.StringType)
+				}
+			})
+		}
+	})
+}
@@ -2,6 +2,8 @@ package scold
 
 import (
 	"bufio"
+	"errors"
+	"math"
 	"strconv"
 	"strings"
 	"unicode"
@@ -9,36 +11,41 @@ import (
 )
 
 // ValidIntMaxLen is maximum number of digits a lexeme may have to be
-// considered an int
-var ValidIntMaxLen = 10
-
-type lexemeType int
-
-// Available lexeme types. The following invariant holds:
-// each type is a specialization of all types whose numerical value is less
-// than that of self. For example, 42 is a float and is an int, but 42.2 is
-// a float but not an int. Hence, int is a specialization of float. A type T
-// is a specialization of a type U if any value of type T is of type U also.
-//
-// The consequence is that between any two types T and U from the list
-// there's always a specialization relationship, but in gerenal, this is not
-// the case. For example: imagine a lexeme type 'hash' that classifies
-// strings of form 2400f9b. The float is not a specialization
-// of hash, because 42.2 is not a hash, and likewise the
-// hash is not a specialization of float, because 2400f9b is not a float.
-const (
-	STRXM lexemeType = iota
-	FLOATXM
-	INTXM
-	FINALXM
-)
+// considered an int. Zero means there's no limit.
+var ValidIntMaxLen = 0
 
-// IsIntLexeme returns true if the string represents a signed integer.
-// Additionally, it should contain not more than VALID_INT_MAX_LEN digits.
+// IsIntLexeme returns true if the string represents a signed integer of
+// any length. Additionally, if ValidIntMaxLen is set, it should contain not
+// more than ValidIntMaxLen digits.
 func IsIntLexeme(xm string) bool {
-	_, err := strconv.Atoi(xm)
+	if _, ok := intMagnitude(xm); !ok {
+		return false
+	}
+
+	digits := strings.TrimLeft(xm, "+-")
+	return ValidIntMaxLen == 0 || len(digits) <= ValidIntMaxLen
+}
+
+// intMagnitude returns the digits of an int lexeme without the sign and the
+// leading zeros, so that the integers of any length could be compared. The
+// magnitude of zero is empty.
+func intMagnitude(xm string) (string, bool) {
+	digits := xm
+	if digits != "" && (digits[0] == '+' || digits[0] == '-') {
+		digits = digits[1:]
+	}
+
+	if digits == "" {
+		return "", false
+	}
+
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return "", false
+		}
+	}
 
-	return err == nil && len(xm) <= ValidIntMaxLen
+	return strings.TrimLeft(digits, "0"), true
 }
 
 // IsFloatLexeme returns true if the string represents a floating-point value.
@@ -65,25 +72,20 @@ func IsFloatLexeme(xm string) bool {
 	return xm != "."
 }
 
-// TypeCheckers defines a list type checking functions (TCF).
-// The type checker for string is omitted because it always returns true.
-// Hence, the index of TCF corresponds to a type of numerical value `index+1`.
-var TypeCheckers = []func(string) bool{
-	IsFloatLexeme,
-	IsIntLexeme,
-}
+// IsScientificFloatLexeme returns true if the string represents
+// a floating-point value in any notation Go understands: the plain one,
+// the scientific one (1e-9, 3.2E+05), the hexadecimal one (0x1.8p3), and
+// the special values (inf, -Inf, nan). Values that overflow float64 are
+// still considered floats.
+func IsScientificFloatLexeme(xm string) bool {
+	// ParseFloat allows underscores between digits, but they are unlikely
+	// to appear in the numbers printed by programs.
+	if strings.ContainsRune(xm, '_') {
+		return false
+	}
 
-// MaskGenerators lists all of the mask generating functions (MGF). MGFs are
-// defined only for arguments of the same type. I.e., there's no MGF for float
-// and int, only for float/float, and int/int. If differnt types must be
-// assessed, the MGF of their common type_ must be called. The common type
-// between two types T and U exists if specialization relationship between them
-// exists and is the least specialized type. The index of MGF in this array
-// corresponds to the numerical value of the type of which MGF's arguments are.
-var MaskGenerators = []func(*Lexer, string, string) []bool{
-	(*Lexer).GenMaskForString,
-	(*Lexer).GenMaskForFloat,
-	(*Lexer).GenMaskForInt,
+	_, err := strconv.ParseFloat(xm, 64)
+	return err == nil || errors.Is(err, strconv.ErrRange)
 }
 
 // IDEA: Add map[string]interface{} for custom configs from outside of library.
@@ -93,6 +95,28 @@ var MaskGenerators = []func(*Lexer, string, string) []bool{
 // Lexer.
 type Lexer struct {
 	Precision uint
+
+	// types holds the lexeme types registered with RegisterType. If nil,
+	// only the builtin types are known.
+	types *lexemeTypes
+
+	// AbsEps and RelEps switch the comparison of floats from digit-wise to
+	// numeric. Two floats are equal if they differ by at most AbsEps or by
+	// at most RelEps times the larger of their absolute values. If both are
+	// zero, Precision is used instead.
+	AbsEps float64
+	RelEps float64
+
+	// IgnoreCase makes the string lexemes equal if they differ only in the
+	// letter case.
+	IgnoreCase bool
+
+	// Order tells whether the lexemes or the lines of the output may be
+	// permuted.
+	Order OrderMode
+
+	// Mode tells whether the text is split into lexemes or into lines.
+	Mode CompareMode
 }
 
 // ScanLexemes is a split function for bufio.Scanner. It is same as
@@ -141,9 +165,14 @@ func ScanLexemes(data []byte, atEOF bool) (advance int, token []byte, err error)
 
 // Scan will break the text into lexemes and return them. A lexeme
 // is either a string consisting of non-unicode.IsSpace characters,
-// or a single newline character.
+// or a single newline character. If the lexer's Mode is not CompareTokens,
+// the lexemes are whole lines instead, see scanLines.
 // If no lexemes found, nil is returned.
 func (l *Lexer) Scan(text string) (xms []string) {
+	if l.Mode != CompareTokens {
+		return l.scanLines(text)
+	}
+
 	r := strings.NewReader(text)
 	s := bufio.NewScanner(r)
 	s.Split(ScanLexemes)
@@ -160,8 +189,21 @@ func (l *Lexer) Scan(text string) (xms []string) {
 // comparison takes place between two non-LF lexems, and the spurious LFs
 // are marked red and skipped. The function is intended to be called twice
 // for the two permutations of the arguments to get error highlighting for
-// both strings.
+// both strings. If the lexer's Order allows permutations, the comparison is
+// delegated to CompareAnyTokenOrder or CompareAnyLineOrder. If the lexer's
+// Mode is not CompareTokens, the LFs are not skipped, see compareLines.
 func (l *Lexer) Compare(target, source []string) (rts []RichText, ok bool) {
+	switch l.Order {
+	case AnyTokenOrder:
+		return l.CompareAnyTokenOrder(target, source)
+	case AnyLineOrder:
+		return l.CompareAnyLineOrder(target, source)
+	}
+
+	if l.Mode != CompareTokens {
+		return l.compareLines(target, source)
+	}
+
 	rts = make([]RichText, len(target))
 	ok = true
 
@@ -187,9 +229,11 @@ func (l *Lexer) Compare(target, source []string) (rts []RichText, ok bool) {
 
 		xm := target[ti]
 		rts[ti].Str = xm
-		rts[ti].Mask = l.GenerateMask(xm, source[si])
 
-		if rts[ti].Colorful() {
+		var equal bool
+		rts[ti].Mask, equal = l.compareLexemes(xm, source[si])
+
+		if !equal {
 			ok = false
 		}
 	}
@@ -204,49 +248,107 @@ func (l *Lexer) Compare(target, source []string) (rts []RichText, ok bool) {
 	return
 }
 
-// deduceLexemeType will assess the type of the lexeme by sequentially applying
-// more and more specialized type checkers starting from the least restrictive
-// one.
-func deduceLexemeType(xm string) lexemeType {
-	for i := int(STRXM) + 1; i != int(FINALXM); i++ {
-		// As any lexeme *is* a string, the function IsStringLexeme is omitted.
-		if !TypeCheckers[i-1](xm) {
-			return lexemeType(i - 1)
-		}
+// RegisterType adds a custom lexeme type to the lexer. The parent type must
+// be registered already, the builtin types are always available. The types
+// are registered in the lexer's own copy of the specialization tree, so the
+// copies of the lexer made before the call are not affected.
+func (l *Lexer) RegisterType(t LexemeType) error {
+	types := l.typeTree().clone()
+
+	err := types.add(t)
+	if err != nil {
+		return err
+	}
+
+	l.types = types
+	return nil
+}
+
+func (l *Lexer) typeTree() *lexemeTypes {
+	if l.types == nil {
+		return builtinLexemeTypes
 	}
 
-	return lexemeType(FINALXM - 1)
+	return l.types
+}
+
+// TypeOf returns the name of the most specialized type of the lexeme.
+func (l *Lexer) TypeOf(xm string) string {
+	return l.typeTree().typeOf(xm).Name
+}
+
+// CommonType returns the name of the most specialized type both lexemes
+// are of.
+func (l *Lexer) CommonType(a, b string) string {
+	types := l.typeTree()
+	return types.common(types.typeOf(a), types.typeOf(b)).Name
 }
 
 // GenerateMask is a wrapper function that finds the common type of the two
 // lexems and generates a color mask for the target based on source.
 func (l *Lexer) GenerateMask(target, source string) []bool {
-	targetType := deduceLexemeType(target)
-	sourceType := deduceLexemeType(source)
+	mask, _ := l.compareLexemes(target, source)
+	return mask
+}
 
-	commonType := targetType
-	if sourceType < commonType {
-		commonType = sourceType
+// compareLexemes compares the lexemes as the values of their common type
+// and generates a color mask for the target based on source. The lines
+// are always compared as strings.
+func (l *Lexer) compareLexemes(target, source string) (mask []bool, equal bool) {
+	if l.Mode != CompareTokens {
+		return l.compareLine(target, source)
 	}
 
-	return MaskGenerators[commonType](l, target, source)
-}
+	types := l.typeTree()
+	t := types.common(types.typeOf(target), types.typeOf(source))
 
-// GenMaskForString will highlight mismatching characters.
-func (l *Lexer) GenMaskForString(target, source string) (mask []bool) {
-	commonLen := len(target)
-	if len(source) < commonLen {
-		commonLen = len(source)
+	if t.GenMask != nil {
+		mask = t.GenMask(l, target, source)
 	}
 
-	mask = make([]bool, len(target))
+	// The target's mask doesn't show that the target is a prefix of the
+	// source, so the source's one is checked too.
+	if t.Equal == nil {
+		equal = !RichText{target, mask}.Colorful() &&
+			!RichText{source, t.GenMask(l, source, target)}.Colorful()
+		return mask, equal
+	}
+
+	equal = t.Equal(l, target, source)
 
-	for i := 0; i < commonLen; i++ {
-		mask[i] = target[i] != source[i]
+	// The mask must show that the lexemes differ.
+	if mask == nil || !equal && !(RichText{target, mask}.Colorful()) {
+		mask = make([]bool, utf8.RuneCountInString(target))
+		for i := range mask {
+			mask[i] = !equal
+		}
 	}
 
-	for i := commonLen; i < len(target); i++ {
-		mask[i] = true
+	return mask, equal
+}
+
+// GenMaskForString will highlight mismatching characters. The strings are
+// compared rune-wise, and the mask has an element for each rune of target.
+// If the lexer ignores the case, the runes are compared using Unicode simple
+// case folding.
+func (l *Lexer) GenMaskForString(target, source string) (mask []bool) {
+	mask = make([]bool, 0, utf8.RuneCountInString(target))
+
+	for ti, si := 0, 0; ti < len(target); {
+		tr, targetWidth := utf8.DecodeRuneInString(target[ti:])
+		sr, sourceWidth := utf8.DecodeRuneInString(source[si:])
+
+		// The invalid bytes are decoded as the same RuneError, so the bytes
+		// themselves are compared.
+		equal := sourceWidth != 0 && target[ti:ti+targetWidth] == source[si:si+sourceWidth]
+		if !equal && l.IgnoreCase && sourceWidth != 0 && tr != utf8.RuneError {
+			equal = equalFold(tr, sr)
+		}
+
+		mask = append(mask, !equal)
+
+		ti += targetWidth
+		si += sourceWidth
 	}
 
 	return
@@ -254,7 +356,7 @@ func (l *Lexer) GenMaskForString(target, source string) (mask []bool) {
 
 // GenMaskForInt will highlight the whole number if at least one digit
 // is different. Independently, the sign will be highlighted if it's different
-// also.
+// also. The sign of zero doesn't matter, -0 is the same as 0.
 func (l *Lexer) GenMaskForInt(target, source string) (mask []bool) {
 	mask = make([]bool, len(target))
 
@@ -262,21 +364,17 @@ func (l *Lexer) GenMaskForInt(target, source string) (mask []bool) {
 		return
 	}
 
-	if target[0] == '-' && source[0] != '-' || target[0] == '+' && source[0] == '-' {
-		mask[0] = true
-	}
-
-	targetVal, _ := strconv.Atoi(target)
-	if targetVal < 0 {
-		targetVal = -targetVal
-	}
+	// GenMaskForFloat passes whole parts like "-" of "-.5", which are zero.
+	// The magnitudes of such parts are empty, as of zero.
+	targetMag, _ := intMagnitude(target)
+	sourceMag, _ := intMagnitude(source)
 
-	sourceVal, _ := strconv.Atoi(source)
-	if sourceVal < 0 {
-		sourceVal = -sourceVal
+	bothZero := targetMag == "" && sourceMag == ""
+	if !bothZero && (target[0] == '-' && source[0] != '-' || target[0] == '+' && source[0] == '-') {
+		mask[0] = true
 	}
 
-	if targetVal != sourceVal {
+	if targetMag != sourceMag {
 		for i := range mask {
 			mask[i] = true
 		}
@@ -288,8 +386,21 @@ func (l *Lexer) GenMaskForInt(target, source string) (mask []bool) {
 // GenMaskForFloat uses the same logic as GenMaskForInt to highlight the
 // whole part. If at least one digit in the fractional part (part after the
 // dot) is different and its index (zero-based) is less than lexer's
-// precision, this digit is highlighted.
+// precision, this digit is highlighted. If the lexer has a tolerance set,
+// the floats are compared numerically instead, and the whole number is
+// highlighted if they are not within the tolerance.
 func (l *Lexer) GenMaskForFloat(target, source string) (mask []bool) {
+	if l.AbsEps != 0 || l.RelEps != 0 {
+		return l.genMaskForFloatWithTolerance(target, source)
+	}
+
+	// -0.0 and 0.0 are the same number, the sign doesn't matter.
+	targetVal, _ := parseFloatLexeme(target)
+	sourceVal, _ := parseFloatLexeme(source)
+	if targetVal == 0 && sourceVal == 0 {
+		return make([]bool, len(target))
+	}
+
 	targetWhole := strings.Split(target, ".")[0]
 
 	sourceWhole := strings.Split(source, ".")[0]
@@ -338,3 +449,215 @@ func (l *Lexer) GenMaskForFloat(target, source string) (mask []bool) {
 
 	return
 }
+
+// GenMaskForScientificFloat compares the floats numerically, since they
+// may be written in different notations. The floats are equal if they are
+// within the lexer's tolerance, or, if no tolerance is set, if they are
+// equal in the decimal notation as GenMaskForFloat tells. Otherwise, the
+// mantissa and the exponent of target are highlighted separately if they
+// differ from those of source. If the notations are too different to tell,
+// the whole number is highlighted.
+func (l *Lexer) GenMaskForScientificFloat(target, source string) (mask []bool) {
+	mask = make([]bool, len(target))
+
+	if target == source {
+		return
+	}
+
+	targetVal, targetOK := parseFloatLexeme(target)
+	sourceVal, sourceOK := parseFloatLexeme(source)
+
+	if targetOK && sourceOK && l.floatsEqual(targetVal, sourceVal) {
+		return
+	}
+
+	targetMant, targetExp := splitExponent(target)
+	sourceMant, sourceExp := splitExponent(source)
+
+	mantEqual := mantissaValue(targetMant) == mantissaValue(sourceMant)
+	expEqual := exponentValue(targetExp) == exponentValue(sourceExp)
+
+	// The parts look the same, but the values are not equal. Either they
+	// overflow, or the tolerance is tighter than the notation.
+	if mantEqual && expEqual {
+		for i := range mask {
+			mask[i] = true
+		}
+
+		return
+	}
+
+	if !mantEqual {
+		for i := 0; i < len(targetMant); i++ {
+			mask[i] = true
+		}
+	}
+
+	if !expEqual && targetExp != "" {
+		for i := len(target) - len(targetExp); i < len(target); i++ {
+			mask[i] = true
+		}
+	} else if !expEqual {
+		// The source has an exponent, but the target doesn't. The whole
+		// target is its mantissa then.
+		for i := range mask {
+			mask[i] = true
+		}
+	}
+
+	return
+}
+
+// genMaskForFloatWithTolerance highlights the whole target if it differs
+// from the source by more than the lexer's tolerance allows.
+func (l *Lexer) genMaskForFloatWithTolerance(target, source string) (mask []bool) {
+	mask = make([]bool, len(target))
+
+	// Numbers too long for float64 are still equal to themselves.
+	if target == source {
+		return
+	}
+
+	targetVal, targetOK := parseFloatLexeme(target)
+	sourceVal, sourceOK := parseFloatLexeme(source)
+
+	if targetOK && sourceOK && l.floatsEqual(targetVal, sourceVal) {
+		return
+	}
+
+	for i := range mask {
+		mask[i] = true
+	}
+
+	return
+}
+
+// floatsEqual tells whether a and b are equal according to the lexer's
+// tolerance. If no tolerance is set, their decimal notations are compared
+// like GenMaskForFloat does, so that the verdict doesn't depend on the
+// notation the numbers are written in. Infinities are equal only to
+// themselves, and NaN is equal to NaN, as judges usually treat them.
+func (l *Lexer) floatsEqual(a, b float64) bool {
+	if a == b || math.IsNaN(a) && math.IsNaN(b) {
+		return true
+	}
+
+	if l.AbsEps == 0 && l.RelEps == 0 {
+		if math.IsInf(a, 0) || math.IsInf(b, 0) || math.IsNaN(a) || math.IsNaN(b) {
+			return false
+		}
+
+		return l.decimalKey(strconv.FormatFloat(a, 'f', -1, 64)) ==
+			l.decimalKey(strconv.FormatFloat(b, 'f', -1, 64))
+	}
+
+	diff := math.Abs(a - b)
+	return diff <= l.AbsEps || diff <= l.RelEps*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// decimalKey reduces an int or a float lexeme in the decimal notation to
+// the part that GenMaskForFloat compares: the sign, the whole part without
+// leading zeros and the first Precision digits of the fractional part. Two
+// such lexemes are equal if and only if their keys are. As in
+// GenMaskForInt, the sign of a zero whole part doesn't matter.
+func (l *Lexer) decimalKey(xm string) string {
+	negative := strings.HasPrefix(xm, "-")
+	whole, frac, _ := strings.Cut(strings.TrimLeft(xm, "+-"), ".")
+
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole, negative = "0", false
+	}
+
+	if len(frac) > int(l.Precision) {
+		frac = frac[:l.Precision]
+	} else {
+		frac += strings.Repeat("0", int(l.Precision)-len(frac))
+	}
+
+	if negative {
+		return "-" + whole + "." + frac
+	}
+
+	return whole + "." + frac
+}
+
+// parseFloatLexeme converts a float lexeme of any notation to float64. The
+// values that overflow float64 are reported as not ok, since they cannot
+// be compared reliably.
+func parseFloatLexeme(xm string) (float64, bool) {
+	val, err := strconv.ParseFloat(xm, 64)
+	return val, err == nil
+}
+
+// splitExponent splits a float lexeme into the mantissa and the exponent
+// digits with their sign. The exponent marker belongs to neither. If there
+// is no exponent, it is empty.
+func splitExponent(xm string) (mantissa, exponent string) {
+	marker := "eE"
+	if isHexFloat(xm) {
+		marker = "pP"
+	}
+
+	i := strings.IndexAny(xm, marker)
+	if i == -1 {
+		return xm, ""
+	}
+
+	return xm[:i], xm[i+1:]
+}
+
+func isHexFloat(xm string) bool {
+	if xm != "" && (xm[0] == '+' || xm[0] == '-') {
+		xm = xm[1:]
+	}
+
+	return strings.HasPrefix(xm, "0x") || strings.HasPrefix(xm, "0X")
+}
+
+// mantissaValue parses the mantissa produced by splitExponent. NaN is
+// returned for the mantissas that cannot be parsed, so that they are
+// equal to nothing.
+func mantissaValue(mantissa string) float64 {
+	if isHexFloat(mantissa) {
+		mantissa += "p0"
+	}
+
+	val, err := strconv.ParseFloat(mantissa, 64)
+	if err != nil {
+		return math.NaN()
+	}
+
+	return val
+}
+
+// exponentValue parses the exponent produced by splitExponent. An empty
+// exponent is zero.
+func exponentValue(exponent string) string {
+	if exponent == "" {
+		return "0"
+	}
+
+	val, err := strconv.Atoi(exponent)
+	if err != nil {
+		return exponent
+	}
+
+	return strconv.Itoa(val)
+}
+
+// equalFold tells whether the runes are equal under simple case folding,
+// i.e., whether one can be turned into the other by changing the case.
+func equalFold(a, b rune) bool {
+	if a == b {
+		return true
+	}
+
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+
+	return false
+}
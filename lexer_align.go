@@ -0,0 +1,188 @@
+package scold
+
+import "unicode/utf8"
+
+// MaxAlignmentSize limits the product of the lengths of the sequences that
+// CompareAligned aligns after skipping their common prefix and suffix. The
+// alignment at the limit takes a few milliseconds. Aligning longer sequences
+// would take too much time and memory, so they are compared position by
+// position instead.
+var MaxAlignmentSize = 1 << 16
+
+// CompareAligned compares the output lexemes against the answer's ones
+// and generates the colored lexemes of both. Unlike Compare, it finds the
+// longest common subsequence of equal lexemes first, so that a single
+// missing or extra lexeme doesn't shift the rest of the output. The output
+// lexemes that are absent in the answer (insertions) are highlighted in
+// richGot, and the answer lexemes that are absent in the output (deletions)
+// are highlighted in richAnswer. If there are as many insertions as
+// deletions between two matched lexemes, they are treated as replacements
+// and highlighted as if compared with Compare.
+func (l *Lexer) CompareAligned(got, answer []string) (richGot, richAnswer []RichText, ok bool) {
+	prefix := 0
+	for prefix < len(got) && prefix < len(answer) && l.lexemesEqual(got[prefix], answer[prefix]) {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(got)-prefix && suffix < len(answer)-prefix &&
+		l.lexemesEqual(got[len(got)-1-suffix], answer[len(answer)-1-suffix]) {
+		suffix++
+	}
+
+	midGot := got[prefix : len(got)-suffix]
+	midAnswer := answer[prefix : len(answer)-suffix]
+
+	richGot = make([]RichText, len(got))
+	richAnswer = make([]RichText, len(answer))
+
+	for i := 0; i < prefix; i++ {
+		l.markEqualPair(&richGot[i], &richAnswer[i], got[i], answer[i])
+	}
+
+	for i := 1; i <= suffix; i++ {
+		l.markEqualPair(&richGot[len(got)-i], &richAnswer[len(answer)-i], got[len(got)-i], answer[len(answer)-i])
+	}
+
+	if len(midGot)*len(midAnswer) > MaxAlignmentSize {
+		midRichGot, okGot := l.Compare(midGot, midAnswer)
+		midRichAnswer, okAnswer := l.Compare(midAnswer, midGot)
+
+		copy(richGot[prefix:], midRichGot)
+		copy(richAnswer[prefix:], midRichAnswer)
+		return richGot, richAnswer, okGot && okAnswer
+	}
+
+	matches := l.longestCommonSubsequence(l.lexemeInfos(midGot), l.lexemeInfos(midAnswer))
+
+	ok = true
+	gi, ai := 0, 0
+	for _, m := range append(matches, lexemePair{len(midGot), len(midAnswer)}) {
+		if gi != m.got || ai != m.answer {
+			ok = false
+			l.markGap(richGot[prefix+gi:prefix+m.got], richAnswer[prefix+ai:prefix+m.answer],
+				midGot[gi:m.got], midAnswer[ai:m.answer])
+		}
+
+		if m.got < len(midGot) {
+			l.markEqualPair(&richGot[prefix+m.got], &richAnswer[prefix+m.answer], midGot[m.got], midAnswer[m.answer])
+		}
+
+		gi, ai = m.got+1, m.answer+1
+	}
+
+	return
+}
+
+// lexemePair holds the indices of two equal lexemes of the output and the
+// answer.
+type lexemePair struct {
+	got, answer int
+}
+
+// SpansEqual tells whether the spans of lexemes, like the lines of the
+// output and of the answer, are equal position by position.
+func (l *Lexer) SpansEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !l.lexemesEqual(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lexemesEqual tells whether two lexemes are equal. The same lexemes are
+// equal unless the custom types may tell otherwise.
+func (l *Lexer) lexemesEqual(a, b string) bool {
+	if a == b && l.types == nil {
+		return true
+	}
+
+	aInfo, bInfo := l.lexemeInfo(a), l.lexemeInfo(b)
+	return l.infosEqual(&aInfo, &bInfo)
+}
+
+// longestCommonSubsequence finds the longest sequence of pairs of equal
+// lexemes of got and answer that go in the same order in both.
+func (l *Lexer) longestCommonSubsequence(got, answer []lexemeInfo) (matches []lexemePair) {
+	n, m := len(got), len(answer)
+
+	// lengths[i*(m+1)+j] is the length of the LCS of got[i:] and answer[j:]
+	lengths := make([]int32, (n+1)*(m+1))
+	equal := make([]bool, n*m)
+
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			equal[i*m+j] = l.infosEqual(&got[i], &answer[j])
+
+			if equal[i*m+j] {
+				lengths[i*(m+1)+j] = lengths[(i+1)*(m+1)+j+1] + 1
+			} else if down, right := lengths[(i+1)*(m+1)+j], lengths[i*(m+1)+j+1]; down >= right {
+				lengths[i*(m+1)+j] = down
+			} else {
+				lengths[i*(m+1)+j] = right
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case equal[i*m+j] && lengths[i*(m+1)+j] == lengths[(i+1)*(m+1)+j+1]+1:
+			matches = append(matches, lexemePair{i, j})
+			i, j = i+1, j+1
+		case lengths[(i+1)*(m+1)+j] >= lengths[i*(m+1)+j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return
+}
+
+// markPair generates the masks of two lexemes compared against each other.
+func (l *Lexer) markPair(gotRT, answerRT *RichText, got, answer string) {
+	gotRT.Str, answerRT.Str = got, answer
+	gotRT.Mask, _ = l.compareLexemes(got, answer)
+	answerRT.Mask, _ = l.compareLexemes(answer, got)
+}
+
+// markEqualPair generates the masks of two lexemes known to be equal. The
+// builtin types highlight nothing in the equal lexemes, so the masks are
+// generated only if custom types are registered.
+func (l *Lexer) markEqualPair(gotRT, answerRT *RichText, got, answer string) {
+	if l.types != nil {
+		l.markPair(gotRT, answerRT, got, answer)
+		return
+	}
+
+	*gotRT = RichText{got, make([]bool, utf8.RuneCountInString(got))}
+	*answerRT = RichText{answer, make([]bool, utf8.RuneCountInString(answer))}
+}
+
+// markGap highlights the unmatched lexemes between two matches. They are
+// paired position by position as replacements, and the rest are highlighted
+// as a whole.
+func (l *Lexer) markGap(gotRTs, answerRTs []RichText, got, answer []string) {
+	common := len(got)
+	if len(answer) < common {
+		common = len(answer)
+	}
+
+	for i := 0; i < common; i++ {
+		l.markPair(&gotRTs[i], &answerRTs[i], got[i], answer[i])
+	}
+
+	for i := common; i < len(got); i++ {
+		gotRTs[i] = RichText{got[i], l.GenMaskForString(got[i], "")}
+	}
+
+	for i := common; i < len(answer); i++ {
+		answerRTs[i] = RichText{answer[i], l.GenMaskForString(answer[i], "")}
+	}
+}
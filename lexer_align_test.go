@@ -0,0 +1,212 @@
+package scold_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/kuredoro/scold"
+)
+
+func TestCompareAligned(t *testing.T) {
+	t.Run("equal sequences", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+
+		got := lexer.Scan("1 2\n3\n")
+		answer := lexer.Scan("1 2\n3\n")
+
+		richGot, richAnswer, ok := lexer.CompareAligned(got, answer)
+
+		want := []scold.RichText{
+			{"1", []bool{false}},
+			{"2", []bool{false}},
+			{"\n", []bool{false}},
+			{"3", []bool{false}},
+			{"\n", []bool{false}},
+		}
+
+		scold.AssertDiffSuccess(t, ok)
+		scold.AssertEnrichedLexSequence(t, richGot, want)
+		scold.AssertEnrichedLexSequence(t, richAnswer, want)
+	})
+
+	t.Run("missing lexeme doesn't shift the rest", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+
+		got := lexer.Scan("2 3 4 5")
+		answer := lexer.Scan("1 2 3 4 5")
+
+		richGot, richAnswer, ok := lexer.CompareAligned(got, answer)
+
+		gotWant := []scold.RichText{
+			{"2", []bool{false}},
+			{"3", []bool{false}},
+			{"4", []bool{false}},
+			{"5", []bool{false}},
+		}
+
+		answerWant := []scold.RichText{
+			{"1", []bool{true}},
+			{"2", []bool{false}},
+			{"3", []bool{false}},
+			{"4", []bool{false}},
+			{"5", []bool{false}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, richGot, gotWant)
+		scold.AssertEnrichedLexSequence(t, richAnswer, answerWant)
+	})
+
+	t.Run("extra lexeme doesn't shift the rest", func(t *testing.T) {
+		lexer := &scold.Lexer{}
+
+		got := lexer.Scan("1 2 42 3\n")
+		answer := lexer.Scan("1 2 3\n")
+
+		richGot, richAnswer, ok := lexer.CompareAligned(got, answer)
+
+		gotWant := []scold.RichText{
+			{"1", []bool{false}},
+			{"2", []bool{false}},
+			{"42", []bool{true, true}},
+			{"3", []bool{false}},
+			{"\n", []bool{false}},
+		}
+
+		answerWant := []scold.RichText{
+			{"1", []bool{false}},
+			{"2", []bool{false}},
+			{"3", []bool{false}},
+			{"\n", []bool{false}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, richGot, gotWant)
+		scold.AssertEnrichedLexSequence(t, richAnswer, answerWant)
+	})
+
+	t.Run("replacements are highlighted according to their types", func(t *testing.T) {
+		lexer := &scold.Lexer{Precision: 3}
+
+		got := lexer.Scan("a 1.25 b x c")
+		answer := lexer.Scan("a 1.26 b c")
+
+		richGot, richAnswer, ok := lexer.CompareAligned(got, answer)
+
+		gotWant := []scold.RichText{
+			{"a", []bool{false}},
+			{"1.25", []bool{false, false, false, true}},
+			{"b", []bool{false}},
+			{"x", []bool{true}},
+			{"c", []bool{false}},
+		}
+
+		answerWant := []scold.RichText{
+			{"a", []bool{false}},
+			{"1.26", []bool{false, false, false, true}},
+			{"b", []bool{false}},
+			{"c", []bool{false}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, richGot, gotWant)
+		scold.AssertEnrichedLexSequence(t, richAnswer, answerWant)
+	})
+
+	t.Run("long sequences are compared position by position", func(t *testing.T) {
+		defer func(size int) { scold.MaxAlignmentSize = size }(scold.MaxAlignmentSize)
+		scold.MaxAlignmentSize = 4
+
+		lexer := &scold.Lexer{}
+
+		got := lexer.Scan("2 3 x")
+		answer := lexer.Scan("1 2 3 y")
+
+		richGot, richAnswer, ok := lexer.CompareAligned(got, answer)
+
+		gotWant, _ := lexer.Compare(got, answer)
+		answerWant, _ := lexer.Compare(answer, got)
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, richGot, gotWant)
+		scold.AssertEnrichedLexSequence(t, richAnswer, answerWant)
+	})
+}
+
+func TestSpansEqual(t *testing.T) {
+	lexer := &scold.Lexer{Precision: 2}
+
+	cases := []struct {
+		name string
+		a, b []string
+		want bool
+	}{
+		{"equal text", []string{"a", "1"}, []string{"a", "1"}, true},
+		{"equal numbers", []string{"1.0", "2"}, []string{"1", "2.001"}, true},
+		{"different lexemes", []string{"1", "2"}, []string{"1", "3"}, false},
+		{"different lengths", []string{"1", "2"}, []string{"1"}, false},
+		{"empty spans", nil, nil, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := lexer.SpansEqual(c.a, c.b); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+// SpansEqual doesn't generate the masks to tell whether the lexemes are
+// equal, but it must agree with the comparison that does.
+func TestSpansEqualAgreesWithCompare(t *testing.T) {
+	xms := []string{
+		"abc", "ABC", "Abc", "ab", "ǅ", "ǆ", "\xff", "a\xffb", "a\xffB",
+		"0", "-0", "+0", "00", "1", "01", "-1", "+1", "10",
+		"1.0", "1.", "1.00001", ".5", "-.5", "-0.5", "0.5", "-0.0",
+		"1.2349", "1.2351", "1.2341", "12345678901234567890", "12345678901234567890.5",
+		"1e0", "1E0", "1.2349e0", "-5e-1", "0x1p0", "0x1P-1",
+		"inf", "-Inf", "nan", "NaN", "1e400", "-1e400",
+	}
+
+	lexers := map[string]*scold.Lexer{
+		"default":           {},
+		"precision":         {Precision: 3},
+		"ignore case":       {Precision: 3, IgnoreCase: true},
+		"absolute eps":      {AbsEps: 0.01},
+		"relative eps":      {RelEps: 1e-3},
+		"lines":             {Mode: scold.CompareLines},
+		"lines ignore case": {Mode: scold.CompareLines, IgnoreCase: true},
+		"exact ignore case": {Mode: scold.CompareExact, IgnoreCase: true},
+	}
+
+	for name, lexer := range lexers {
+		t.Run(name, func(t *testing.T) {
+			for _, a := range xms {
+				for _, b := range xms {
+					_, want := lexer.Compare([]string{a}, []string{b})
+
+					if got := lexer.SpansEqual([]string{a}, []string{b}); got != want {
+						t.Errorf("%q against %q: got equal %v, want %v", a, b, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkCompareAligned compares the outputs that differ everywhere, so
+// that the alignment is as large as MaxAlignmentSize allows.
+func BenchmarkCompareAligned(b *testing.B) {
+	var got, answer []string
+	for i := 0; len(got)*len(answer) < scold.MaxAlignmentSize; i++ {
+		got = append(got, strconv.Itoa(2*i)+".5")
+		answer = append(answer, strconv.Itoa(2*i+1)+".5")
+	}
+
+	lexer := &scold.Lexer{Precision: 6}
+
+	for i := 0; i < b.N; i++ {
+		lexer.CompareAligned(got, answer)
+	}
+}
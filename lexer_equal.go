@@ -0,0 +1,185 @@
+package scold
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The depths of the builtin types in the specialization tree. Since they
+// form a chain, the common type of two builtin types is the shallower one.
+const (
+	customTypeDepth = iota - 1
+	stringTypeDepth
+	scientificFloatTypeDepth
+	floatTypeDepth
+	intTypeDepth
+)
+
+var builtinTypeDepth = map[string]int{
+	StringType:          stringTypeDepth,
+	ScientificFloatType: scientificFloatTypeDepth,
+	FloatType:           floatTypeDepth,
+	IntType:             intTypeDepth,
+}
+
+// lexemeInfo holds what's needed to tell whether a lexeme is equal to
+// another one, so that the algorithms comparing each lexeme against many
+// others, like the alignment, classify and parse it only once.
+type lexemeInfo struct {
+	xm string
+	t  *LexemeType
+
+	// depth is the depth of the builtin type t, or customTypeDepth.
+	depth int
+
+	// folded is the lexeme with the case folded, if the case is ignored.
+	folded string
+
+	// decimal is the decimalKey of the int and float lexemes.
+	decimal string
+
+	// value is the value of the numeric lexemes, and valueKey is what
+	// floatsEqual compares when no tolerance is set. It's computed only when
+	// needed, since the scifloats are rare.
+	value    float64
+	valueOK  bool
+	valueKey string
+}
+
+// lexemeInfos precomputes the lexemeInfo of each lexeme.
+func (l *Lexer) lexemeInfos(xms []string) []lexemeInfo {
+	infos := make([]lexemeInfo, len(xms))
+	for i, xm := range xms {
+		infos[i] = l.lexemeInfo(xm)
+	}
+
+	return infos
+}
+
+func (l *Lexer) lexemeInfo(xm string) lexemeInfo {
+	info := lexemeInfo{xm: xm, folded: xm}
+
+	if l.IgnoreCase && l.Mode != CompareExact {
+		info.folded = foldString(xm)
+	}
+
+	if l.Mode != CompareTokens {
+		return info
+	}
+
+	info.t = l.typeTree().typeOf(xm)
+
+	depth, ok := builtinTypeDepth[info.t.Name]
+	if !ok {
+		info.depth = customTypeDepth
+		return info
+	}
+
+	info.depth = depth
+
+	if depth >= floatTypeDepth {
+		info.decimal = l.decimalKey(xm)
+	}
+
+	if depth >= scientificFloatTypeDepth {
+		info.value, info.valueOK = parseFloatLexeme(xm)
+	}
+
+	return info
+}
+
+// valueKey is what floatsEqual compares when no tolerance is set.
+func (l *Lexer) valueKey(val float64) string {
+	switch {
+	case math.IsNaN(val):
+		return "nan"
+	case math.IsInf(val, 1):
+		return "+inf"
+	case math.IsInf(val, -1):
+		return "-inf"
+	}
+
+	return l.decimalKey(strconv.FormatFloat(val, 'f', -1, 64))
+}
+
+// infosEqual tells whether the lexemes are equal the same way
+// compareLexemes does, but without generating the masks.
+func (l *Lexer) infosEqual(a, b *lexemeInfo) bool {
+	if l.Mode != CompareTokens {
+		return a.folded == b.folded
+	}
+
+	// The custom types may compare the lexemes in any way.
+	if a.depth == customTypeDepth || b.depth == customTypeDepth {
+		_, equal := l.compareLexemes(a.xm, b.xm)
+		return equal
+	}
+
+	depth := a.depth
+	if b.depth < depth {
+		depth = b.depth
+	}
+
+	switch depth {
+	case stringTypeDepth:
+		return a.folded == b.folded
+	case scientificFloatTypeDepth:
+		return a.xm == b.xm || a.valueOK && b.valueOK && l.valuesEqual(a, b)
+	case floatTypeDepth:
+		if l.AbsEps != 0 || l.RelEps != 0 {
+			return a.xm == b.xm || a.valueOK && b.valueOK && l.floatsEqual(a.value, b.value)
+		}
+	}
+
+	return a.decimal == b.decimal
+}
+
+func (l *Lexer) valuesEqual(a, b *lexemeInfo) bool {
+	if l.AbsEps != 0 || l.RelEps != 0 {
+		return l.floatsEqual(a.value, b.value)
+	}
+
+	for _, info := range []*lexemeInfo{a, b} {
+		if info.valueKey == "" {
+			info.valueKey = l.valueKey(info.value)
+		}
+	}
+
+	return a.valueKey == b.valueKey
+}
+
+// foldString maps each rune of s to the smallest rune it is equal to under
+// simple case folding, so that the strings equal as GenMaskForString tells
+// with the case ignored have the same folded forms. The invalid bytes are
+// kept as is.
+func foldString(s string) string {
+	var folded strings.Builder
+	folded.Grow(len(s))
+
+	for i := 0; i < len(s); {
+		r, width := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && width == 1 {
+			folded.WriteByte(s[i])
+		} else {
+			folded.WriteRune(foldRune(r))
+		}
+
+		i += width
+	}
+
+	return folded.String()
+}
+
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+
+	return min
+}
@@ -0,0 +1,85 @@
+package scold
+
+import (
+	"strings"
+	"unicode"
+)
+
+// scanLines breaks the text into lines separated by LF lexemes. The empty
+// lines produce no lexemes besides the LFs. In CompareLines mode, the
+// trailing whitespace of each line and of the whole text is dropped. In
+// CompareExact mode, the text is kept intact, so that concatenating the
+// lexemes gives back the text.
+func (l *Lexer) scanLines(text string) (xms []string) {
+	if l.Mode == CompareLines {
+		text = strings.TrimRightFunc(text, unicode.IsSpace)
+	}
+
+	for text != "" {
+		end := strings.IndexByte(text, '\n')
+		if end == -1 {
+			end = len(text)
+		}
+
+		line := text[:end]
+		if l.Mode == CompareLines {
+			line = strings.TrimRightFunc(line, unicode.IsSpace)
+		}
+
+		if line != "" {
+			xms = append(xms, line)
+		}
+
+		if end != len(text) {
+			xms = append(xms, "\n")
+			end++
+		}
+
+		text = text[end:]
+	}
+
+	return
+}
+
+// compareLines compares the lines of target and source position by
+// position. Unlike in Compare, the LFs are compared as any other lexeme,
+// so the missing and the surplus empty lines are highlighted too.
+func (l *Lexer) compareLines(target, source []string) (rts []RichText, ok bool) {
+	rts = make([]RichText, len(target))
+	ok = len(target) == len(source)
+
+	for i, xm := range target {
+		rts[i].Str = xm
+
+		if i >= len(source) {
+			rts[i].Mask = l.GenMaskForString(xm, "")
+			continue
+		}
+
+		var equal bool
+		rts[i].Mask, equal = l.compareLexemes(xm, source[i])
+
+		if !equal {
+			ok = false
+		}
+	}
+
+	return
+}
+
+// compareLine compares two lines as strings. In CompareExact mode, the
+// letter case always matters.
+func (l *Lexer) compareLine(target, source string) (mask []bool, equal bool) {
+	lx := l
+	if l.Mode == CompareExact && l.IgnoreCase {
+		exact := *l
+		exact.IgnoreCase = false
+		lx = &exact
+	}
+
+	mask = lx.GenMaskForString(target, source)
+	equal = !RichText{target, mask}.Colorful() &&
+		!RichText{source, lx.GenMaskForString(source, target)}.Colorful()
+
+	return mask, equal
+}
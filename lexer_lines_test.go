@@ -0,0 +1,182 @@
+package scold_test
+
+import (
+	"testing"
+
+	"github.com/kuredoro/scold"
+)
+
+func TestLexerScanLines(t *testing.T) {
+	cases := []struct {
+		Title string
+		Mode  scold.CompareMode
+		Text  string
+		Want  []string
+	}{
+		{"lines keep inner whitespace", scold.CompareLines, "a  b\tc\n", []string{"a  b\tc"}},
+		{"lines drop trailing whitespace", scold.CompareLines, "a \t\r\n b \n\n \n", []string{"a", "\n", " b"}},
+		{"empty lines are LFs", scold.CompareLines, "a\n\n\nb", []string{"a", "\n", "\n", "\n", "b"}},
+		{"blank text in lines mode", scold.CompareLines, " \n\t\n", nil},
+		{"exact keeps everything", scold.CompareExact, "a \r\n\n b", []string{"a \r", "\n", "\n", " b"}},
+		{"exact keeps final LF", scold.CompareExact, "a\n", []string{"a", "\n"}},
+		{"empty text", scold.CompareExact, "", nil},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Title, func(t *testing.T) {
+			lexer := &scold.Lexer{Mode: test.Mode}
+
+			got := lexer.Scan(test.Text)
+
+			scold.AssertLexemes(t, got, test.Want)
+		})
+	}
+}
+
+func TestLexerCompareLines(t *testing.T) {
+	t.Run("trailing whitespace is ignored in lines mode", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines}
+
+		target := lexer.Scan("#.# \n.#.\n\n")
+		source := lexer.Scan("#.#\n.#.")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"#.#", []bool{false, false, false}},
+			{"\n", []bool{false}},
+			{".#.", []bool{false, false, false}},
+		}
+
+		scold.AssertDiffSuccess(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("inner whitespace matters", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines}
+
+		target := lexer.Scan("1  2\n")
+		source := lexer.Scan("1 2\n")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"1  2", []bool{false, false, true, true}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("prefix of a line is not equal to it", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines}
+
+		_, ok := lexer.Compare(lexer.Scan("ab"), lexer.Scan("abc"))
+
+		scold.AssertDiffFailure(t, ok)
+	})
+
+	t.Run("empty lines are not skipped", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines}
+
+		target := lexer.Scan("a\n\nb\n")
+		source := lexer.Scan("a\nb\n")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"a", []bool{false}},
+			{"\n", []bool{false}},
+			{"\n", []bool{true}},
+			{"b", []bool{true}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("numbers are compared as strings", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines, Precision: 1}
+
+		_, ok := lexer.Compare(lexer.Scan("1.00"), lexer.Scan("1.0"))
+
+		scold.AssertDiffFailure(t, ok)
+	})
+
+	t.Run("exact mode requires trailing whitespace and case to match", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareExact, IgnoreCase: true}
+
+		target := lexer.Scan("Yes \n")
+		source := lexer.Scan("yes\n")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"Yes ", []bool{true, false, false, true}},
+			{"\n", []bool{false}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("exact mode requires the final LF", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareExact}
+
+		target := lexer.Scan("a")
+		source := lexer.Scan("a\n")
+
+		_, okTarget := lexer.Compare(target, source)
+		got, okSource := lexer.Compare(source, target)
+
+		want := []scold.RichText{
+			{"a", []bool{false}},
+			{"\n", []bool{true}},
+		}
+
+		scold.AssertDiffFailure(t, okTarget && okSource)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("surplus lines are highlighted", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines}
+
+		got, ok := lexer.Compare(lexer.Scan("a\nb"), nil)
+
+		want := []scold.RichText{
+			{"a", []bool{true}},
+			{"\n", []bool{true}},
+			{"b", []bool{true}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("lines are aligned", func(t *testing.T) {
+		lexer := &scold.Lexer{Mode: scold.CompareLines}
+
+		got := lexer.Scan("x\n..#\n.#.\n")
+		answer := lexer.Scan("..#\n.#.\n")
+
+		richGot, richAnswer, ok := lexer.CompareAligned(got, answer)
+
+		gotWant := []scold.RichText{
+			{"x", []bool{true}},
+			{"\n", []bool{true}},
+			{"..#", []bool{false, false, false}},
+			{"\n", []bool{false}},
+			{".#.", []bool{false, false, false}},
+		}
+
+		answerWant := []scold.RichText{
+			{"..#", []bool{false, false, false}},
+			{"\n", []bool{false}},
+			{".#.", []bool{false, false, false}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, richGot, gotWant)
+		scold.AssertEnrichedLexSequence(t, richAnswer, answerWant)
+	})
+}
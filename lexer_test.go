@@ -121,6 +121,27 @@ func TestLexerCompare(t *testing.T) {
 		scold.AssertEnrichedLexSequence(t, got, want)
 	})
 
+	t.Run("mixed float notations are compared numerically", func(t *testing.T) {
+		target := []string{"1e-9", "0.000000001", "3.2E+05", "-0.0", "inf", "2e3"}
+		source := []string{"0.000000001", "1e-9", "320000", "0", "+Inf", "2000.5"}
+
+		lexer := &scold.Lexer{Precision: 8}
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{target[0], []bool{false, false, false, false}},
+			{target[1], make([]bool, 11)},
+			{target[2], make([]bool, 7)},
+			{target[3], []bool{false, false, false, false}},
+			{target[4], []bool{false, false, false}},
+			{target[5], []bool{true, false, true}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
 	t.Run("spurious LFs are skipped in target", func(t *testing.T) {
 		target := []string{"foo", "\n", "\n", "bar"}
 		source := []string{"foo", "\n", "bar"}
@@ -203,6 +224,70 @@ func TestGenMaskForString(t *testing.T) {
 	})
 }
 
+func TestGenMaskForStringMultibyte(t *testing.T) {
+	lexer := &scold.Lexer{}
+
+	cases := []struct {
+		Target, Source string
+		Want           []bool
+	}{
+		{"привет", "привет", []bool{false, false, false, false, false, false}},
+		{"привет", "привед", []bool{false, false, false, false, false, true}},
+		{"привет", "при", []bool{false, false, false, true, true, true}},
+		{"при", "привет", []bool{false, false, false}},
+		{"日本語", "日本", []bool{false, false, true}},
+		{"日本", "日本語", []bool{false, false}},
+		{"a日b", "aéb", []bool{false, true, false}},
+		{"aéb", "a日b", []bool{false, true, false}},
+		{"x\xffy", "x\xfey", []bool{false, true, false}},
+		{"x\xffy", "x\xffy", []bool{false, false, false}},
+	}
+
+	for _, test := range cases {
+		title := fmt.Sprintf("%q against %q", test.Target, test.Source)
+		t.Run(title, func(t *testing.T) {
+			got := lexer.GenMaskForString(test.Target, test.Source)
+
+			scold.AssertRichTextMask(t, got, test.Want)
+		})
+	}
+}
+
+func TestGenMaskForStringIgnoreCase(t *testing.T) {
+	lexer := &scold.Lexer{IgnoreCase: true}
+
+	cases := []struct {
+		Target, Source string
+		Want           []bool
+	}{
+		{"Yes", "YES", []bool{false, false, false}},
+		{"yes", "No", []bool{true, true, true}},
+		{"YeS", "yEp", []bool{false, false, true}},
+		{"Привет", "пРИВЕТ", make([]bool, 6)},
+		{"Ωmega", "ωMEGA", make([]bool, 5)},
+		{"Kelvin", "\u212aelvin", []bool{false, false, false, false, false, false}},
+		{"äb", "Ac", []bool{true, true}},
+		{"abc", "AB", []bool{false, false, true}},
+		{"ab", "ABC", []bool{false, false}},
+	}
+
+	for _, test := range cases {
+		title := fmt.Sprintf("%s against %s", test.Target, test.Source)
+		t.Run(title, func(t *testing.T) {
+			got := lexer.GenMaskForString(test.Target, test.Source)
+
+			scold.AssertRichTextMask(t, got, test.Want)
+		})
+	}
+
+	t.Run("case matters by default", func(t *testing.T) {
+		got := (&scold.Lexer{}).GenMaskForString("Yes", "YES")
+		want := []bool{false, true, true}
+
+		scold.AssertRichTextMask(t, got, want)
+	})
+}
+
 func TestIsIntLexeme(t *testing.T) {
 	cases := []struct {
 		Str  string
@@ -217,8 +302,12 @@ func TestIsIntLexeme(t *testing.T) {
 		{"10+-", false},
 		{"0", true},
 		{"0xa", false},
-		{strings.Repeat("1", scold.ValidIntMaxLen), true},
-		{strings.Repeat("1", scold.ValidIntMaxLen+1), false},
+		{strings.Repeat("1", 10), true},
+		{strings.Repeat("9", 300), true},
+		{"-" + strings.Repeat("9", 300), true},
+		{"+007", true},
+		{"1_000", false},
+		{"", false},
 	}
 
 	for _, test := range cases {
@@ -234,6 +323,19 @@ func TestIsIntLexeme(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("digit count is limited by ValidIntMaxLen", func(t *testing.T) {
+		defer func(oldLen int) { scold.ValidIntMaxLen = oldLen }(scold.ValidIntMaxLen)
+		scold.ValidIntMaxLen = 10
+
+		if !scold.IsIntLexeme("-" + strings.Repeat("1", 10)) {
+			t.Errorf("got 10-digit number is not INT, but it is")
+		}
+
+		if scold.IsIntLexeme(strings.Repeat("1", 11)) {
+			t.Errorf("got 11-digit number is INT, but it isn't")
+		}
+	})
 }
 
 func TestGenMaskForInt(t *testing.T) {
@@ -255,6 +357,14 @@ func TestGenMaskForInt(t *testing.T) {
 		{"-10", "+10", []bool{true, false, false}},
 		{"", "10", []bool{}},
 		{"10", "", []bool{false, false}},
+		{"-0", "0", []bool{false, false}},
+		{"+0", "-0", []bool{false, false}},
+		{"-0", "+000", []bool{false, false}},
+		{"18446744073709551616", "18446744073709551616", make([]bool, 20)},
+		{"18446744073709551617", "18446744073709551616", trueMask(20)},
+		{"-18446744073709551616", "18446744073709551616", append([]bool{true}, make([]bool, 20)...)},
+		{"+" + strings.Repeat("9", 200), strings.Repeat("9", 200), make([]bool, 201)},
+		{strings.Repeat("9", 200) + "8", strings.Repeat("9", 201), trueMask(201)},
 	}
 
 	for _, test := range cases {
@@ -326,6 +436,8 @@ func TestGenMaskForFloat(t *testing.T) {
 		{"0.5", ".5", []bool{false, false, false}},
 		{"-10.5", "10.0", []bool{true, false, false, false, true}},
 		{"-11.5", "10.0", []bool{true, true, true, false, true}},
+		{"-0.0", "0.0", []bool{false, false, false, false}},
+		{"0.0", "-0.00", []bool{false, false, false}},
 	}
 
 	for _, test := range cases {
@@ -337,3 +449,155 @@ func TestGenMaskForFloat(t *testing.T) {
 		})
 	}
 }
+
+func TestGenMaskForFloatWithTolerance(t *testing.T) {
+	cases := []struct {
+		AbsEps, RelEps float64
+		Target, Source string
+		Want           []bool
+	}{
+		{1e-6, 0, "0.29999999", "0.3", []bool{false, false, false, false, false, false, false, false, false, false}},
+		{1e-6, 0, "0.3", "0.29999999", []bool{false, false, false}},
+		{1e-6, 0, "0.3", "0.31", []bool{true, true, true}},
+		{1e-6, 0, "-0.0", "0", []bool{false, false, false, false}},
+		{0, 1e-6, "1000000.5", "1000000", []bool{false, false, false, false, false, false, false, false, false}},
+		{0, 1e-6, "1000002", "1000000.0", []bool{true, true, true, true, true, true, true}},
+		{0, 1e-6, "-1.0", "1.0", []bool{true, true, true, true}},
+		{1e-9, 1e-9, "0.000000001", "0", []bool{false, false, false, false, false, false, false, false, false, false, false}},
+		{1e-9, 1e-9, "1" + strings.Repeat("0", 400), "1" + strings.Repeat("0", 400), make([]bool, 401)},
+	}
+
+	for _, test := range cases {
+		title := fmt.Sprintf("%s against %s (abs %g, rel %g)", test.Target, test.Source, test.AbsEps, test.RelEps)
+		t.Run(title, func(t *testing.T) {
+			lexer := &scold.Lexer{
+				Precision: 2,
+				AbsEps:    test.AbsEps,
+				RelEps:    test.RelEps,
+			}
+
+			got := lexer.GenMaskForFloat(test.Target, test.Source)
+
+			scold.AssertRichTextMask(t, got, test.Want)
+		})
+	}
+}
+
+func TestIsScientificFloatLexeme(t *testing.T) {
+	cases := []struct {
+		Str  string
+		Want bool
+	}{
+		{"10", true},
+		{"-10.5", true},
+		{".5", true},
+		{"1e-9", true},
+		{"3.2E+05", true},
+		{"-1.5e10", true},
+		{"1e400", true},
+		{"0x1.8p3", true},
+		{"inf", true},
+		{"-Inf", true},
+		{"nan", true},
+		{"NaN", true},
+		{"e5", false},
+		{"1e", false},
+		{"1e5.5", false},
+		{"0x10", false},
+		{"infinite", false},
+		{"1_000", false},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Str, func(t *testing.T) {
+			got := scold.IsScientificFloatLexeme(test.Str)
+
+			if got != test.Want {
+				if test.Want {
+					t.Errorf("got '%s' is not SCIFLOAT, but it is", test.Str)
+				} else {
+					t.Errorf("got '%s' is SCIFLOAT, but it isn't", test.Str)
+				}
+			}
+		})
+	}
+}
+
+func TestGenMaskForScientificFloat(t *testing.T) {
+	lexer := &scold.Lexer{
+		Precision: 2,
+	}
+
+	cases := []struct {
+		Target, Source string
+		Want           []bool
+	}{
+		{"1e-9", "0.000000001", []bool{false, false, false, false}},
+		{"1.001e2", "100.104", []bool{false, false, false, false, false, false, false}},
+		{"1.5e3", "1.5E3", []bool{false, false, false, false, false}},
+		{"1.5e3", "1.6e3", []bool{true, true, true, false, false}},
+		{"1.5e3", "1.5e4", []bool{false, false, false, false, true}},
+		{"1.5e+03", "1.6e+04", []bool{true, true, true, false, true, true, true}},
+		{"15e2", "1.5e3", []bool{false, false, false, false}},
+		{"15e2", "1.6e3", []bool{true, true, false, true}},
+		{"1500", "1.6e3", []bool{true, true, true, true}},
+		{"0x1p-2", "0.25", []bool{false, false, false, false, false, false}},
+		{"0x1p-2", "0x1p-3", []bool{false, false, false, false, true, true}},
+		{"-0.0e0", "0", []bool{false, false, false, false, false, false}},
+		{"inf", "-inf", []bool{true, true, true}},
+		{"inf", "1e308", []bool{true, true, true}},
+		{"nan", "NaN", []bool{false, false, false}},
+		{"nan", "0", []bool{true, true, true}},
+		{"1e400", "1e400", []bool{false, false, false, false, false}},
+		{"1e400", "2e400", []bool{true, false, false, false, false}},
+	}
+
+	for _, test := range cases {
+		title := fmt.Sprintf("%s against %s", test.Target, test.Source)
+		t.Run(title, func(t *testing.T) {
+			got := lexer.GenMaskForScientificFloat(test.Target, test.Source)
+
+			scold.AssertRichTextMask(t, got, test.Want)
+		})
+	}
+}
+
+func TestFloatNotationsCompareAlike(t *testing.T) {
+	cases := []struct {
+		A, B      string
+		Precision uint
+		Equal     bool
+	}{
+		{"1.0000001", "1.0", 6, true},
+		{"1.0000001", "1.0", 7, false},
+		{"1.2349", "1.2351", 3, false},
+		{"1.2341", "1.2349", 3, true},
+		{"-2.5", "-2.59", 1, true},
+		{"-2.5", "2.5", 1, false},
+		{"120", "120.09", 1, true},
+	}
+
+	for _, test := range cases {
+		lexer := &scold.Lexer{Precision: test.Precision}
+
+		// The exponent makes the lexeme a scifloat, but keeps its value.
+		for _, a := range []string{test.A, test.A + "e0", test.A + "E+00"} {
+			title := fmt.Sprintf("%s against %s with precision %d", a, test.B, test.Precision)
+			t.Run(title, func(t *testing.T) {
+				_, ok := lexer.Compare([]string{a}, []string{test.B})
+				if ok != test.Equal {
+					t.Errorf("got equal %v, want %v", ok, test.Equal)
+				}
+			})
+		}
+	}
+}
+
+func trueMask(n int) []bool {
+	mask := make([]bool, n)
+	for i := range mask {
+		mask[i] = true
+	}
+
+	return mask
+}
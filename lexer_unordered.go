@@ -0,0 +1,386 @@
+package scold
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CompareAnyTokenOrder compares the multisets of the non-LF lexemes of
+// target and source. The lexemes of target that have no equal pair in
+// source are highlighted as a whole, and the LFs are ignored. Like Compare,
+// it's intended to be called twice to highlight the surplus on both sides.
+func (l *Lexer) CompareAnyTokenOrder(target, source []string) (rts []RichText, ok bool) {
+	targetItems := tokenItems(target)
+	sourceItems := tokenItems(source)
+
+	matched := l.matchItems(targetItems, sourceItems)
+
+	return highlightUnmatched(target, targetItems, matched)
+}
+
+// CompareAnyLineOrder compares the multisets of the lines of target and
+// source. Two lines are equal if their lexemes are equal position by
+// position. The lines of target that have no equal pair in source are
+// highlighted as a whole. The empty lines are ignored. Like Compare, it's
+// intended to be called twice to highlight the surplus on both sides.
+func (l *Lexer) CompareAnyLineOrder(target, source []string) (rts []RichText, ok bool) {
+	targetItems := lineItems(target)
+	sourceItems := lineItems(source)
+
+	matched := l.matchItems(targetItems, sourceItems)
+
+	return highlightUnmatched(target, targetItems, matched)
+}
+
+// unorderedItem is a token or a line: a span of lexemes that is matched as
+// a whole.
+type unorderedItem struct {
+	begin, end int
+	lexemes    []string
+}
+
+func (it unorderedItem) key() string {
+	return strings.Join(it.lexemes, " ")
+}
+
+// tokenItems makes an item of each non-LF lexeme.
+func tokenItems(xms []string) (items []unorderedItem) {
+	for i, xm := range xms {
+		if xm != "\n" {
+			items = append(items, unorderedItem{i, i + 1, xms[i : i+1]})
+		}
+	}
+
+	return
+}
+
+// lineItems makes an item of each non-empty line.
+func lineItems(xms []string) (items []unorderedItem) {
+	begin := 0
+	for i := 0; i <= len(xms); i++ {
+		if i != len(xms) && xms[i] != "\n" {
+			continue
+		}
+
+		if begin != i {
+			items = append(items, unorderedItem{begin, i, xms[begin:i]})
+		}
+
+		begin = i + 1
+	}
+
+	return
+}
+
+// MaxMatchingSize limits the product of the numbers of the target and
+// source items that may be equal to each other, for which matchItems
+// searches for the maximum matching. Such items are paired greedily in
+// sorted order instead, which is optimal for the single numbers but may
+// miss pairs of the lines.
+var MaxMatchingSize = 1 << 16
+
+// matchItems pairs the items of target with the equal items of source and
+// tells which target items got a pair. Since the numbers are equal within
+// a tolerance, the equality is not transitive, and the pairs are searched for
+// as a maximum bipartite matching. The identical items are paired first, so
+// that the usual outputs are matched in linear time. Then the items that may
+// be equal are grouped into buckets, and the rest are paired within each
+// bucket using augmenting paths, which may rearrange the pairs found so far.
+func (l *Lexer) matchItems(target, source []unorderedItem) (matched []bool) {
+	m := itemMatching{
+		lexer:       l,
+		target:      l.itemInfos(target),
+		source:      l.itemInfos(source),
+		sourceOwner: make([]int, len(source)),
+		visited:     make([]int, len(source)),
+	}
+
+	for j := range m.sourceOwner {
+		m.sourceOwner[j] = -1
+	}
+
+	matched = make([]bool, len(target))
+
+	unusedByKey := make(map[string][]int)
+	for j, item := range source {
+		key := item.key()
+		unusedByKey[key] = append(unusedByKey[key], j)
+	}
+
+	for i, item := range target {
+		key := item.key()
+		if unused := unusedByKey[key]; len(unused) != 0 {
+			m.sourceOwner[unused[0]] = i
+			unusedByKey[key] = unused[1:]
+			matched[i] = true
+		}
+	}
+
+	for _, b := range m.buckets(matched) {
+		if len(b.target)*len(b.source) <= MaxMatchingSize {
+			m.augmentAll(b, matched)
+		} else {
+			m.sweep(b)
+		}
+	}
+
+	for i := range matched {
+		matched[i] = false
+	}
+
+	for _, i := range m.sourceOwner {
+		if i != -1 {
+			matched[i] = true
+		}
+	}
+
+	return
+}
+
+func (l *Lexer) itemInfos(items []unorderedItem) [][]lexemeInfo {
+	infos := make([][]lexemeInfo, len(items))
+	for i, item := range items {
+		infos[i] = l.lexemeInfos(item.lexemes)
+	}
+
+	return infos
+}
+
+// itemMatching is the state of the search for the augmenting paths in the
+// bipartite graph of the equal target and source items.
+type itemMatching struct {
+	lexer  *Lexer
+	target [][]lexemeInfo
+	source [][]lexemeInfo
+
+	// sourceOwner holds the index of the target item paired with each source
+	// item, or -1.
+	sourceOwner []int
+
+	// adjacent holds the indices of the source items equal to each target
+	// item of the bucket being matched.
+	adjacent map[int][]int
+
+	// visited holds the number of the search that last visited each source
+	// item, so that it needn't be cleared before each search.
+	visited []int
+	search  int
+}
+
+// itemBucket holds the indices of the target and source items that may be
+// equal to each other. The items of different buckets are never equal.
+type itemBucket struct {
+	target, source []int
+	hasUnmatched   bool
+}
+
+// buckets groups the items by bucketKey. Only the buckets with unmatched
+// target items and with source items are returned, in the order of their
+// first unmatched target items.
+func (m *itemMatching) buckets(matched []bool) []*itemBucket {
+	byKey := make(map[string]*itemBucket)
+
+	var order []*itemBucket
+	for i, item := range m.target {
+		key := m.lexer.bucketKey(item)
+
+		b := byKey[key]
+		if b == nil {
+			b = &itemBucket{}
+			byKey[key] = b
+		}
+
+		if !matched[i] && !b.hasUnmatched {
+			b.hasUnmatched = true
+			order = append(order, b)
+		}
+
+		b.target = append(b.target, i)
+	}
+
+	for j, item := range m.source {
+		if b := byKey[m.lexer.bucketKey(item)]; b != nil {
+			b.source = append(b.source, j)
+		}
+	}
+
+	var buckets []*itemBucket
+	for _, b := range order {
+		if len(b.source) != 0 {
+			buckets = append(buckets, b)
+		}
+	}
+
+	return buckets
+}
+
+// bucketKey is equal for the items that may be equal. The numbers may be
+// equal to each other in many ways, so they are all keyed the same, and
+// the other lexemes are equal only if their folded forms are. The custom
+// types may tell any lexemes equal, so all items are keyed the same if
+// they are registered.
+func (l *Lexer) bucketKey(item []lexemeInfo) string {
+	if l.types != nil {
+		return ""
+	}
+
+	keys := make([]string, len(item))
+	for i := range item {
+		if item[i].valueOK {
+			keys[i] = "#"
+		} else {
+			keys[i] = item[i].folded
+		}
+	}
+
+	return strings.Join(keys, " ")
+}
+
+// augmentAll pairs the unmatched target items of the bucket using
+// augmenting paths.
+func (m *itemMatching) augmentAll(b *itemBucket, matched []bool) {
+	m.adjacent = make(map[int][]int, len(b.target))
+	for _, i := range b.target {
+		for _, j := range b.source {
+			if m.lexer.itemsEqual(m.target[i], m.source[j]) {
+				m.adjacent[i] = append(m.adjacent[i], j)
+			}
+		}
+	}
+
+	for _, i := range b.target {
+		if !matched[i] {
+			m.search++
+			m.augment(i)
+		}
+	}
+}
+
+// augment tries to find a pair for the target item i, possibly by
+// re-pairing the target items that are already paired.
+func (m *itemMatching) augment(i int) bool {
+	for _, j := range m.adjacent[i] {
+		if m.visited[j] == m.search {
+			continue
+		}
+
+		m.visited[j] = m.search
+		if m.sourceOwner[j] == -1 || m.augment(m.sourceOwner[j]) {
+			m.sourceOwner[j] = i
+			return true
+		}
+	}
+
+	return false
+}
+
+// sweep pairs the items of the bucket greedily: both sides are sorted, and
+// the smaller of the two current items is skipped until they are equal.
+// All items of the bucket are re-paired, since the identical items paired
+// first may be needed to pair the others.
+func (m *itemMatching) sweep(b *itemBucket) {
+	for _, j := range b.source {
+		m.sourceOwner[j] = -1
+	}
+
+	sort.SliceStable(b.target, func(x, y int) bool {
+		return compareItems(m.target[b.target[x]], m.target[b.target[y]]) < 0
+	})
+
+	sort.SliceStable(b.source, func(x, y int) bool {
+		return compareItems(m.source[b.source[x]], m.source[b.source[y]]) < 0
+	})
+
+	for x, y := 0, 0; x < len(b.target) && y < len(b.source); {
+		i, j := b.target[x], b.source[y]
+
+		switch {
+		case m.lexer.itemsEqual(m.target[i], m.source[j]):
+			m.sourceOwner[j] = i
+			x, y = x+1, y+1
+		case compareItems(m.target[i], m.source[j]) < 0:
+			x++
+		default:
+			y++
+		}
+	}
+}
+
+func (l *Lexer) itemsEqual(a, b []lexemeInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k := range a {
+		if !l.infosEqual(&a[k], &b[k]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareItems orders the items lexeme by lexeme. The numbers are ordered
+// by their values and go before the other lexemes, which are ordered by
+// their folded forms.
+func compareItems(a, b []lexemeInfo) int {
+	for k := 0; k < len(a) && k < len(b); k++ {
+		if c := compareInfos(&a[k], &b[k]); c != 0 {
+			return c
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+func compareInfos(a, b *lexemeInfo) int {
+	switch {
+	case a.valueOK && b.valueOK:
+		return compareValues(a.value, b.value)
+	case a.valueOK:
+		return -1
+	case b.valueOK:
+		return 1
+	}
+
+	return strings.Compare(a.folded, b.folded)
+}
+
+// compareValues orders the floats, placing NaN before the other values.
+func compareValues(a, b float64) int {
+	switch {
+	case a == b || math.IsNaN(a) && math.IsNaN(b):
+		return 0
+	case math.IsNaN(a) || a < b:
+		return -1
+	}
+
+	return 1
+}
+
+// highlightUnmatched highlights the lexemes of the target items that have
+// no pair. The lexemes outside of the items are left as is.
+func highlightUnmatched(target []string, items []unorderedItem, matched []bool) (rts []RichText, ok bool) {
+	rts = make([]RichText, len(target))
+	for i, xm := range target {
+		rts[i] = RichText{xm, make([]bool, utf8.RuneCountInString(xm))}
+	}
+
+	ok = true
+	for i, item := range items {
+		if matched[i] {
+			continue
+		}
+
+		ok = false
+		for j := item.begin; j < item.end; j++ {
+			for k := range rts[j].Mask {
+				rts[j].Mask[k] = true
+			}
+		}
+	}
+
+	return
+}
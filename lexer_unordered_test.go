@@ -0,0 +1,262 @@
+package scold_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuredoro/scold"
+)
+
+func TestCompareAnyTokenOrder(t *testing.T) {
+	t.Run("permutation is accepted", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyTokenOrder}
+
+		target := lexer.Scan("3 1\n2\n")
+		source := lexer.Scan("1 2 3\n")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"3", []bool{false}},
+			{"1", []bool{false}},
+			{"\n", []bool{false}},
+			{"2", []bool{false}},
+			{"\n", []bool{false}},
+		}
+
+		scold.AssertDiffSuccess(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("surplus and missing tokens are highlighted", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyTokenOrder}
+
+		target := lexer.Scan("2 2 10 1")
+		source := lexer.Scan("1 2 3 4")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"2", []bool{false}},
+			{"2", []bool{true}},
+			{"10", []bool{true, true}},
+			{"1", []bool{false}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+
+		got, ok = lexer.Compare(source, target)
+
+		want = []scold.RichText{
+			{"1", []bool{false}},
+			{"2", []bool{false}},
+			{"3", []bool{true}},
+			{"4", []bool{true}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("tokens are compared according to their types", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyTokenOrder, Precision: 2, IgnoreCase: true}
+
+		target := lexer.Scan("0.501 YES 1e2")
+		source := lexer.Scan("100 yes 0.5")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"0.501", make([]bool, 5)},
+			{"YES", make([]bool, 3)},
+			{"1e2", make([]bool, 3)},
+		}
+
+		scold.AssertDiffSuccess(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("tokens equal within tolerance are paired optimally", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyTokenOrder, AbsEps: 0.15}
+
+		target := lexer.Scan("1.0 1.2")
+		source := lexer.Scan("1.1 0.9")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"1.0", make([]bool, 3)},
+			{"1.2", make([]bool, 3)},
+		}
+
+		scold.AssertDiffSuccess(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("identical tokens may be re-paired", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyTokenOrder, AbsEps: 0.15}
+
+		target := lexer.Scan("1.0 1.1")
+		source := lexer.Scan("1.1 1.2")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"1.0", make([]bool, 3)},
+			{"1.1", make([]bool, 3)},
+		}
+
+		scold.AssertDiffSuccess(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("many tokens are paired in sorted order", func(t *testing.T) {
+		defer func(size int) { scold.MaxMatchingSize = size }(scold.MaxMatchingSize)
+		scold.MaxMatchingSize = 1
+
+		lexer := &scold.Lexer{Order: scold.AnyTokenOrder, AbsEps: 0.15}
+
+		target := lexer.Scan("1.1 x 1.0 2")
+		source := lexer.Scan("1.2 x 3 1.1")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"1.1", make([]bool, 3)},
+			{"x", make([]bool, 1)},
+			{"1.0", make([]bool, 3)},
+			{"2", []bool{true}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("many wrong tokens are compared quickly", func(t *testing.T) {
+		target, source := wrongUnorderedOutputs(5000, " ")
+
+		lexer := &scold.Lexer{Order: scold.AnyTokenOrder}
+
+		start := time.Now()
+		_, ok := lexer.Compare(lexer.Scan(target), lexer.Scan(source))
+		elapsed := time.Since(start)
+
+		scold.AssertDiffFailure(t, ok)
+
+		if elapsed > time.Second {
+			t.Errorf("comparison took %v, want it to take less than a second", elapsed)
+		}
+	})
+}
+
+func BenchmarkCompareAnyTokenOrder(b *testing.B) {
+	lexer := &scold.Lexer{Order: scold.AnyTokenOrder}
+
+	target, source := wrongUnorderedOutputs(5000, " ")
+	targetXms, sourceXms := lexer.Scan(target), lexer.Scan(source)
+
+	for i := 0; i < b.N; i++ {
+		lexer.Compare(targetXms, sourceXms)
+	}
+}
+
+// wrongUnorderedOutputs makes two outputs of n numbers, none of which are
+// equal to each other.
+func wrongUnorderedOutputs(n int, sep string) (target, source string) {
+	var targetBuf, sourceBuf strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&targetBuf, "%d%s", 2*i, sep)
+		fmt.Fprintf(&sourceBuf, "%d%s", 2*i+1, sep)
+	}
+
+	return targetBuf.String(), sourceBuf.String()
+}
+
+func TestCompareAnyLineOrder(t *testing.T) {
+	t.Run("permutation of lines is accepted", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyLineOrder}
+
+		target := lexer.Scan("1 2\n\n3 4\n")
+		source := lexer.Scan("3 4\n1 2")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"1", []bool{false}},
+			{"2", []bool{false}},
+			{"\n", []bool{false}},
+			{"\n", []bool{false}},
+			{"3", []bool{false}},
+			{"4", []bool{false}},
+			{"\n", []bool{false}},
+		}
+
+		scold.AssertDiffSuccess(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("tokens within a line must keep their order", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyLineOrder}
+
+		target := lexer.Scan("2 1\n3 4\n3 4\n")
+		source := lexer.Scan("3 4\n1 2\n")
+
+		got, ok := lexer.Compare(target, source)
+
+		want := []scold.RichText{
+			{"2", []bool{true}},
+			{"1", []bool{true}},
+			{"\n", []bool{false}},
+			{"3", []bool{false}},
+			{"4", []bool{false}},
+			{"\n", []bool{false}},
+			{"3", []bool{true}},
+			{"4", []bool{true}},
+			{"\n", []bool{false}},
+		}
+
+		scold.AssertDiffFailure(t, ok)
+		scold.AssertEnrichedLexSequence(t, got, want)
+	})
+
+	t.Run("lines are compared lexeme-wise", func(t *testing.T) {
+		lexer := &scold.Lexer{Order: scold.AnyLineOrder, Precision: 3}
+
+		target := lexer.Scan("2 1.0000\n1 2.5")
+		source := lexer.Scan("1 2.500\n2 1")
+
+		_, ok := lexer.Compare(target, source)
+
+		scold.AssertDiffSuccess(t, ok)
+	})
+
+	t.Run("many wrong lines are compared quickly", func(t *testing.T) {
+		target, source := wrongUnorderedOutputs(2000, " 1\n")
+
+		lexer := &scold.Lexer{Order: scold.AnyLineOrder}
+
+		start := time.Now()
+		_, ok := lexer.Compare(lexer.Scan(target), lexer.Scan(source))
+		elapsed := time.Since(start)
+
+		scold.AssertDiffFailure(t, ok)
+
+		if elapsed > time.Second {
+			t.Errorf("comparison took %v, want it to take less than a second", elapsed)
+		}
+	})
+}
+
+func BenchmarkCompareAnyLineOrder(b *testing.B) {
+	lexer := &scold.Lexer{Order: scold.AnyLineOrder}
+
+	target, source := wrongUnorderedOutputs(2000, " 1\n")
+	targetXms, sourceXms := lexer.Scan(target), lexer.Scan(source)
+
+	for i := 0; i < b.N; i++ {
+		lexer.Compare(targetXms, sourceXms)
+	}
+}
@@ -4,14 +4,38 @@ import (
 	"context"
 	"io"
 	"sync"
+	"time"
 )
 
 // ExecutionResult contains the text printed to stdout and stderr by the process
 // and the exit code returned upon termination.
+//
+// The rest of the fields describe the resources consumed by the process,
+// if the Processer was able to measure them: the CPU time spent in user and
+// kernel mode, the maximum resident set size and the number of voluntary
+// and involuntary context switches.
+//
+// If the process was run interactively, i.e., it communicated with another
+// process called interactor, the interactor's result is stored in Interactor
+// and the exchange between the two is logged in Transcript.
 type ExecutionResult struct {
 	ExitCode int
 	Stdout   string
 	Stderr   string
+
+	UserTime               time.Duration
+	SystemTime             time.Duration
+	PeakMemory             ByteSize
+	VoluntaryCtxSwitches   int64
+	InvoluntaryCtxSwitches int64
+
+	Interactor *ExecutionResult
+	Transcript string
+}
+
+// CPUTime returns the total CPU time consumed by the process.
+func (r ExecutionResult) CPUTime() time.Duration {
+	return r.UserTime + r.SystemTime
 }
 
 // Processer interface abstracts away the concept of the executable under
@@ -0,0 +1,141 @@
+package reporters
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	"github.com/kuredoro/scold"
+)
+
+// DefaultHTMLTitle is the title of the HTML report if HTMLReporter's Title is
+// empty.
+const DefaultHTMLTitle = "scold report"
+
+// HighlightClass is the class of the span elements that wrap the
+// highlighted parts of the outputs and of the answers in the HTML report.
+const HighlightClass = "hl"
+
+//go:embed html_report.tmpl
+var htmlReportTemplateText string
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"seconds": func(s float64) string {
+		return fmt.Sprintf("%.3fs", s)
+	},
+	"bytes": func(b uint64) string {
+		return scold.ByteSize(b).String()
+	},
+	"describe": func(verdict string) string {
+		if verdict == scold.OK.String() {
+			return "passed"
+		}
+
+		return verdictDescriptions[verdict]
+	},
+}).Parse(htmlReportTemplateText))
+
+type htmlTest struct {
+	TestReport
+
+	// RichOutHTML and RichAnswerHTML are the highlighted output and answer
+	// rendered with scold.DumpLexemesHTML.
+	RichOutHTML    template.HTML
+	RichAnswerHTML template.HTML
+
+	// TimeShare is the time of the test in percents of the longest time.
+	TimeShare float64
+}
+
+type htmlVerdictCount struct {
+	Verdict string
+	Count   int
+}
+
+type htmlReport struct {
+	Title    string
+	Config   ConfigReport
+	Summary  SummaryReport
+	Verdicts []htmlVerdictCount
+	Tests    []htmlTest
+}
+
+// HTMLReporter implements scold.TestingEventListener and writes
+// a self-contained HTML report to W when the suite finishes. The report
+// works offline and contains a table of the verdicts with the timing bars,
+// the filters by verdict, and a collapsible section for each test with its
+// input, the answer and the output highlighted side by side.
+type HTMLReporter struct {
+	W     io.Writer
+	Title string
+
+	tests []htmlTest
+	err   error
+}
+
+// NewHTMLReporter creates an HTMLReporter whose report is titled title.
+func NewHTMLReporter(w io.Writer, title string) *HTMLReporter {
+	return &HTMLReporter{W: w, Title: title}
+}
+
+// TestStarted does nothing.
+func (r *HTMLReporter) TestStarted(int) {}
+
+// TestFinished records the result of the test and renders its highlighted
+// output and answer.
+func (r *HTMLReporter) TestFinished(test *scold.Test, result *scold.TestResult) {
+	r.tests = append(r.tests, htmlTest{
+		TestReport:     NewTestReport(test, result),
+		RichOutHTML:    template.HTML(scold.DumpLexemesHTML(result.RichOut, HighlightClass)),
+		RichAnswerHTML: template.HTML(scold.DumpLexemesHTML(result.RichAnswer, HighlightClass)),
+	})
+}
+
+// SuiteFinished writes the report.
+func (r *HTMLReporter) SuiteFinished(b *scold.TestingBatch) {
+	sort.Slice(r.tests, func(i, j int) bool {
+		return r.tests[i].ID < r.tests[j].ID
+	})
+
+	reports := make([]TestReport, len(r.tests))
+	for i, test := range r.tests {
+		reports[i] = test.TestReport
+	}
+
+	suite := NewSuiteReport(b.Config(), reports)
+
+	for i := range r.tests {
+		if suite.Summary.MaxTime != 0 {
+			r.tests[i].TimeShare = 100 * r.tests[i].Time / suite.Summary.MaxTime
+		}
+	}
+
+	var verdicts []htmlVerdictCount
+	for verdict, count := range suite.Summary.Verdicts {
+		verdicts = append(verdicts, htmlVerdictCount{verdict, count})
+	}
+
+	sort.Slice(verdicts, func(i, j int) bool {
+		return verdicts[i].Verdict < verdicts[j].Verdict
+	})
+
+	title := r.Title
+	if title == "" {
+		title = DefaultHTMLTitle
+	}
+
+	r.err = htmlReportTemplate.Execute(r.W, htmlReport{
+		Title:    title,
+		Config:   suite.Config,
+		Summary:  suite.Summary,
+		Verdicts: verdicts,
+		Tests:    r.tests,
+	})
+}
+
+// Err returns the error encountered while writing the report.
+func (r *HTMLReporter) Err() error {
+	return r.err
+}
@@ -0,0 +1,68 @@
+package reporters_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kuredoro/scold/reporters"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestHTMLReporter(t *testing.T) {
+	t.Run("report contains the tests", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := reporters.NewHTMLReporter(&buf, "inputs.txt")
+
+		runReporter(reporter)
+
+		td.CmpNoError(t, reporter.Err())
+
+		got := buf.String()
+
+		wantParts := []string{
+			"<title>inputs.txt</title>",
+			"inputs.txt: 1/2 passed",
+			`<input type="checkbox" data-filter="OK" checked>`,
+			`<input type="checkbox" data-filter="WA" checked>`,
+			`<details class="test" id="test-1" data-verdict="OK">`,
+			`<details class="test" id="test-2" data-verdict="WA" open>`,
+			`<div style="width: 100.0%">`,
+			`<div style="width: 33.3%">`,
+			`<pre>fo<span class="hl">re</span></pre>`,
+			`<pre>fo<span class="hl">ur</span>` + "\n</pre>",
+			`<pre>debug</pre>`,
+		}
+
+		for _, part := range wantParts {
+			if !strings.Contains(got, part) {
+				t.Errorf("got report without %q", part)
+			}
+		}
+
+		if strings.Index(got, `id="test-1"`) > strings.Index(got, `id="test-2"`) {
+			t.Errorf("got tests out of order in the report")
+		}
+	})
+
+	t.Run("outputs are escaped", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := reporters.NewHTMLReporter(&buf, "<script>")
+
+		runReporter(reporter)
+
+		if strings.Contains(buf.String(), "<title><script>") {
+			t.Errorf("got unescaped title in the report")
+		}
+	})
+
+	t.Run("errors are reported", func(t *testing.T) {
+		reporter := reporters.NewHTMLReporter(failingWriter{}, "")
+
+		runReporter(reporter)
+
+		if reporter.Err() == nil {
+			t.Errorf("got no error, want one")
+		}
+	})
+}
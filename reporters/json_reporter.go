@@ -0,0 +1,268 @@
+package reporters
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/kuredoro/scold"
+)
+
+// The values of the Event field of the JSON Lines records.
+const (
+	TestFinishedEvent  = "test_finished"
+	SuiteFinishedEvent = "suite_finished"
+)
+
+// LexemeReport is a lexeme of the output or of the answer together with its
+// highlighting mask. The mask has an element for each rune of the lexeme.
+type LexemeReport struct {
+	Text string `json:"text"`
+	Mask []bool `json:"mask"`
+}
+
+// TestReport describes the result of a single test. The times are in
+// seconds, and the memory is in bytes. The resource usage that is unknown
+// on the platform is zero.
+type TestReport struct {
+	ID      int    `json:"id"`
+	Verdict string `json:"verdict"`
+
+	Time       float64 `json:"time"`
+	CPUTime    float64 `json:"cpu_time"`
+	PeakMemory uint64  `json:"peak_memory"`
+	ExitCode   int     `json:"exit_code"`
+
+	VoluntaryCtxSwitches   int64 `json:"voluntary_ctx_switches"`
+	InvoluntaryCtxSwitches int64 `json:"involuntary_ctx_switches"`
+
+	Input  string `json:"input"`
+	Answer string `json:"answer"`
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+
+	// AnswerIndex is the index of the alternative answer that Answer and
+	// RichAnswer show.
+	AnswerIndex int `json:"answer_index"`
+
+	RichOut    []LexemeReport `json:"rich_out"`
+	RichAnswer []LexemeReport `json:"rich_answer"`
+
+	CheckerMessage string `json:"checker_message,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ConfigReport describes the configuration of the suite. The limits are in
+// seconds and bytes, and zero means there's no limit.
+type ConfigReport struct {
+	Tl     float64 `json:"tl"`
+	TlMode string  `json:"tl_mode"`
+	Ml     uint64  `json:"ml"`
+	Ol     uint64  `json:"ol"`
+
+	Prec    uint8   `json:"prec"`
+	AbsEps  float64 `json:"abs_eps"`
+	RelEps  float64 `json:"rel_eps"`
+	Case    string  `json:"case"`
+	Order   string  `json:"order"`
+	Compare string  `json:"compare"`
+}
+
+// SummaryReport counts the tests by their verdicts. MaxTime and MaxCPUTime
+// are in seconds.
+type SummaryReport struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+
+	Verdicts map[string]int `json:"verdicts"`
+
+	MaxTime    float64 `json:"max_time"`
+	MaxCPUTime float64 `json:"max_cpu_time"`
+}
+
+// SuiteReport is the document describing the whole run. The tests are
+// sorted by their IDs.
+type SuiteReport struct {
+	Config  ConfigReport  `json:"config"`
+	Tests   []TestReport  `json:"tests"`
+	Summary SummaryReport `json:"summary"`
+}
+
+// JSONReporter implements scold.TestingEventListener and writes a JSON
+// report of the testing to W. By default, a single SuiteReport document is
+// written when the suite finishes. If Lines is set, the report is streamed
+// in the JSON Lines format instead: a TestReport is written as soon as
+// each test finishes, and the config and the summary are written last.
+// Every record has an additional "event" field that tells them apart.
+type JSONReporter struct {
+	W     io.Writer
+	Lines bool
+
+	tests []TestReport
+	err   error
+}
+
+// NewJSONReporter creates a JSONReporter that writes a single document.
+func NewJSONReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{W: w}
+}
+
+// NewJSONLinesReporter creates a JSONReporter that streams JSON Lines.
+func NewJSONLinesReporter(w io.Writer) *JSONReporter {
+	return &JSONReporter{W: w, Lines: true}
+}
+
+// TestStarted does nothing.
+func (r *JSONReporter) TestStarted(int) {}
+
+// TestFinished records the result of the test, and writes it right away if
+// the reporter streams JSON Lines.
+func (r *JSONReporter) TestFinished(test *scold.Test, result *scold.TestResult) {
+	report := NewTestReport(test, result)
+
+	if r.Lines {
+		r.write(struct {
+			Event string `json:"event"`
+			TestReport
+		}{TestFinishedEvent, report})
+	}
+
+	r.tests = append(r.tests, report)
+}
+
+// SuiteFinished writes the whole report, or the config and the summary if
+// the reporter streams JSON Lines.
+func (r *JSONReporter) SuiteFinished(b *scold.TestingBatch) {
+	suite := NewSuiteReport(b.Config(), r.tests)
+
+	if r.Lines {
+		r.write(struct {
+			Event   string        `json:"event"`
+			Config  ConfigReport  `json:"config"`
+			Summary SummaryReport `json:"summary"`
+		}{SuiteFinishedEvent, suite.Config, suite.Summary})
+
+		return
+	}
+
+	r.write(suite)
+}
+
+// Err returns the first error encountered while writing the report.
+func (r *JSONReporter) Err() error {
+	return r.err
+}
+
+func (r *JSONReporter) write(v interface{}) {
+	if r.err != nil {
+		return
+	}
+
+	r.err = json.NewEncoder(r.W).Encode(v)
+}
+
+// NewTestReport collects the information about the test and its result.
+func NewTestReport(test *scold.Test, result *scold.TestResult) TestReport {
+	report := TestReport{
+		ID:      result.ID,
+		Verdict: result.Verdict.String(),
+
+		Time:       result.Time.Seconds(),
+		CPUTime:    result.Out.CPUTime().Seconds(),
+		PeakMemory: uint64(result.Out.PeakMemory),
+		ExitCode:   result.Out.ExitCode,
+
+		VoluntaryCtxSwitches:   result.Out.VoluntaryCtxSwitches,
+		InvoluntaryCtxSwitches: result.Out.InvoluntaryCtxSwitches,
+
+		Input:  test.Input,
+		Stdout: result.Out.Stdout,
+		Stderr: result.Out.Stderr,
+
+		AnswerIndex: result.AnswerIndex,
+
+		RichOut:    lexemeReports(result.RichOut),
+		RichAnswer: lexemeReports(result.RichAnswer),
+
+		CheckerMessage: result.CheckerMessage,
+	}
+
+	if answers := test.Answers(); result.AnswerIndex < len(answers) {
+		report.Answer = answers[result.AnswerIndex]
+	}
+
+	if result.Err != nil {
+		report.Error = result.Err.Error()
+	}
+
+	return report
+}
+
+func lexemeReports(rts []scold.RichText) []LexemeReport {
+	reports := make([]LexemeReport, len(rts))
+	for i, rt := range rts {
+		reports[i] = LexemeReport{rt.Str, rt.Mask}
+	}
+
+	return reports
+}
+
+// NewSuiteReport sorts the test reports by their IDs and summarizes them.
+func NewSuiteReport(config scold.InputsConfig, tests []TestReport) SuiteReport {
+	sorted := make([]TestReport, len(tests))
+	copy(sorted, tests)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return SuiteReport{
+		Config:  NewConfigReport(config),
+		Tests:   sorted,
+		Summary: NewSummaryReport(sorted),
+	}
+}
+
+// NewConfigReport converts the suite's configuration.
+func NewConfigReport(config scold.InputsConfig) ConfigReport {
+	return ConfigReport{
+		Tl:     config.Tl.Seconds(),
+		TlMode: config.TlMode.String(),
+		Ml:     uint64(config.Ml),
+		Ol:     uint64(config.Ol),
+
+		Prec:    config.Prec,
+		AbsEps:  config.AbsEps,
+		RelEps:  config.RelEps,
+		Case:    config.Case.String(),
+		Order:   config.Order.String(),
+		Compare: config.Compare.String(),
+	}
+}
+
+// NewSummaryReport counts the tests by their verdicts and finds the longest
+// running times.
+func NewSummaryReport(tests []TestReport) SummaryReport {
+	summary := SummaryReport{
+		Total:    len(tests),
+		Verdicts: make(map[string]int),
+	}
+
+	for _, test := range tests {
+		summary.Verdicts[test.Verdict]++
+
+		if test.Verdict == scold.OK.String() {
+			summary.Passed++
+		}
+
+		if test.Time > summary.MaxTime {
+			summary.MaxTime = test.Time
+		}
+
+		if test.CPUTime > summary.MaxCPUTime {
+			summary.MaxCPUTime = test.CPUTime
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,200 @@
+package reporters_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kuredoro/scold"
+	"github.com/kuredoro/scold/reporters"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+var reportedInputs = scold.Inputs{
+	Tests: []scold.Test{
+		{Input: "1 2\n", Output: "3\n"},
+		{Input: "2 2\n", Output: "4\n", AltOutputs: []string{"four\n"}},
+	},
+	Config: scold.InputsConfig{
+		Tl:   scold.NewPositiveDuration(2 * time.Second),
+		Prec: 6,
+		Case: scold.CaseInsensitive,
+	},
+}
+
+func reportedResults() []*scold.TestResult {
+	ok := &scold.TestResult{
+		RichOut:    []scold.RichText{{Str: "3", Mask: []bool{false}}, {Str: "\n", Mask: []bool{false}}},
+		RichAnswer: []scold.RichText{{Str: "3", Mask: []bool{false}}, {Str: "\n", Mask: []bool{false}}},
+		Verdict:    scold.OK,
+		Time:       1500 * time.Millisecond,
+	}
+	ok.ID = 1
+	ok.Out = scold.ExecutionResult{Stdout: "3\n", UserTime: time.Second}
+
+	wa := &scold.TestResult{
+		RichOut:     []scold.RichText{{Str: "fore", Mask: []bool{false, false, true, true}}},
+		RichAnswer:  []scold.RichText{{Str: "four", Mask: []bool{false, false, true, true}}, {Str: "\n", Mask: []bool{false}}},
+		Verdict:     scold.WA,
+		Time:        500 * time.Millisecond,
+		AnswerIndex: 1,
+	}
+	wa.ID = 2
+	wa.Out = scold.ExecutionResult{
+		Stdout:                 "fore",
+		Stderr:                 "debug",
+		ExitCode:               0,
+		PeakMemory:             scold.Megabyte,
+		VoluntaryCtxSwitches:   5,
+		InvoluntaryCtxSwitches: 2,
+	}
+
+	return []*scold.TestResult{ok, wa}
+}
+
+func wantTestReports() []reporters.TestReport {
+	return []reporters.TestReport{
+		{
+			ID:      1,
+			Verdict: "OK",
+			Time:    1.5,
+			CPUTime: 1,
+			Input:   "1 2\n",
+			Answer:  "3\n",
+			Stdout:  "3\n",
+			RichOut: []reporters.LexemeReport{
+				{Text: "3", Mask: []bool{false}},
+				{Text: "\n", Mask: []bool{false}},
+			},
+			RichAnswer: []reporters.LexemeReport{
+				{Text: "3", Mask: []bool{false}},
+				{Text: "\n", Mask: []bool{false}},
+			},
+		},
+		{
+			ID:         2,
+			Verdict:    "WA",
+			Time:       0.5,
+			PeakMemory: 1 << 20,
+
+			VoluntaryCtxSwitches:   5,
+			InvoluntaryCtxSwitches: 2,
+
+			Input:       "2 2\n",
+			Answer:      "four\n",
+			Stdout:      "fore",
+			Stderr:      "debug",
+			AnswerIndex: 1,
+			RichOut: []reporters.LexemeReport{
+				{Text: "fore", Mask: []bool{false, false, true, true}},
+			},
+			RichAnswer: []reporters.LexemeReport{
+				{Text: "four", Mask: []bool{false, false, true, true}},
+				{Text: "\n", Mask: []bool{false}},
+			},
+		},
+	}
+}
+
+var wantConfigReport = reporters.ConfigReport{
+	Tl:      2,
+	TlMode:  "wall",
+	Prec:    6,
+	Case:    "insensitive",
+	Order:   "strict",
+	Compare: "tokens",
+}
+
+var wantSummaryReport = reporters.SummaryReport{
+	Total:      2,
+	Passed:     1,
+	Verdicts:   map[string]int{"OK": 1, "WA": 1},
+	MaxTime:    1.5,
+	MaxCPUTime: 1,
+}
+
+// runReporter feeds the results to the reporter in the reverse order, like
+// if the second test finished first.
+func runReporter(reporter scold.TestingEventListener) {
+	results := reportedResults()
+	for i := len(results) - 1; i >= 0; i-- {
+		reporter.TestStarted(results[i].ID)
+		reporter.TestFinished(&reportedInputs.Tests[i], results[i])
+	}
+
+	reporter.SuiteFinished(scold.NewTestingBatch(reportedInputs, nil, nil, nil))
+}
+
+func TestJSONReporter(t *testing.T) {
+	t.Run("single document", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := reporters.NewJSONReporter(&buf)
+
+		runReporter(reporter)
+
+		var got reporters.SuiteReport
+		err := json.Unmarshal(buf.Bytes(), &got)
+
+		td.CmpNoError(t, err)
+		td.CmpNoError(t, reporter.Err())
+		td.Cmp(t, got, reporters.SuiteReport{
+			Config:  wantConfigReport,
+			Tests:   wantTestReports(),
+			Summary: wantSummaryReport,
+		})
+	})
+
+	t.Run("JSON lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := reporters.NewJSONLinesReporter(&buf)
+
+		runReporter(reporter)
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		td.Cmp(t, len(lines), 3)
+
+		wantTests := wantTestReports()
+		for i, id := range []int{2, 1} {
+			var got struct {
+				Event string
+				reporters.TestReport
+			}
+			err := json.Unmarshal([]byte(lines[i]), &got)
+
+			td.CmpNoError(t, err)
+			td.Cmp(t, got.Event, reporters.TestFinishedEvent)
+			td.Cmp(t, got.TestReport, wantTests[id-1])
+		}
+
+		var got struct {
+			Event   string
+			Config  reporters.ConfigReport
+			Summary reporters.SummaryReport
+		}
+		err := json.Unmarshal([]byte(lines[2]), &got)
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, got.Event, reporters.SuiteFinishedEvent)
+		td.Cmp(t, got.Config, wantConfigReport)
+		td.Cmp(t, got.Summary, wantSummaryReport)
+	})
+
+	t.Run("errors are reported", func(t *testing.T) {
+		reporter := reporters.NewJSONLinesReporter(failingWriter{})
+
+		runReporter(reporter)
+
+		td.Cmp(t, reporter.Err(), errWriteFailed)
+	})
+}
+
+var errWriteFailed = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWriteFailed
+}
@@ -0,0 +1,201 @@
+package reporters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kuredoro/scold"
+)
+
+// DefaultJUnitSuiteName is the name of the test suite in the JUnit report if
+// JUnitReporter's Name is empty.
+const DefaultJUnitSuiteName = "scold"
+
+var verdictDescriptions = map[string]string{
+	scold.IE.String():  "internal error",
+	scold.WA.String():  "wrong answer",
+	scold.RE.String():  "runtime error",
+	scold.TL.String():  "time limit exceeded",
+	scold.ML.String():  "memory limit exceeded",
+	scold.OLE.String(): "output limit exceeded",
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// JUnitReporter implements scold.TestingEventListener and writes a JUnit
+// XML report to W when the suite finishes. Each test is a testcase. The
+// tests that the executable failed have a failure element, and the tests
+// that failed with IE have an error element, since it's not the
+// executable's fault. The suite's configuration is listed in the
+// properties.
+type JUnitReporter struct {
+	W    io.Writer
+	Name string
+
+	tests []TestReport
+	err   error
+}
+
+// NewJUnitReporter creates a JUnitReporter that names the suite name.
+func NewJUnitReporter(w io.Writer, name string) *JUnitReporter {
+	return &JUnitReporter{W: w, Name: name}
+}
+
+// TestStarted does nothing.
+func (r *JUnitReporter) TestStarted(int) {}
+
+// TestFinished records the result of the test.
+func (r *JUnitReporter) TestFinished(test *scold.Test, result *scold.TestResult) {
+	r.tests = append(r.tests, NewTestReport(test, result))
+}
+
+// SuiteFinished writes the report.
+func (r *JUnitReporter) SuiteFinished(b *scold.TestingBatch) {
+	suite := NewSuiteReport(b.Config(), r.tests)
+
+	name := r.Name
+	if name == "" {
+		name = DefaultJUnitSuiteName
+	}
+
+	junitSuite := junitTestSuite{
+		Name:       name,
+		Tests:      len(suite.Tests),
+		Properties: junitProperties(suite.Config),
+	}
+
+	var totalTime float64
+	for _, test := range suite.Tests {
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("Test %d", test.ID),
+			ClassName: name,
+			Time:      junitTime(test.Time),
+			SystemOut: test.Stdout,
+			SystemErr: test.Stderr,
+		}
+
+		switch test.Verdict {
+		case scold.OK.String():
+		case scold.IE.String():
+			testCase.Error = junitProblemOf(test)
+			junitSuite.Errors++
+		default:
+			testCase.Failure = junitProblemOf(test)
+			junitSuite.Failures++
+		}
+
+		totalTime += test.Time
+		junitSuite.TestCases = append(junitSuite.TestCases, testCase)
+	}
+
+	junitSuite.Time = junitTime(totalTime)
+
+	r.write(junitTestSuites{Suites: []junitTestSuite{junitSuite}})
+}
+
+// Err returns the error encountered while writing the report.
+func (r *JUnitReporter) Err() error {
+	return r.err
+}
+
+func (r *JUnitReporter) write(v interface{}) {
+	_, r.err = io.WriteString(r.W, xml.Header)
+	if r.err != nil {
+		return
+	}
+
+	enc := xml.NewEncoder(r.W)
+	enc.Indent("", "  ")
+
+	r.err = enc.Encode(v)
+	if r.err != nil {
+		return
+	}
+
+	_, r.err = io.WriteString(r.W, "\n")
+}
+
+func junitTime(seconds float64) string {
+	return strconv.FormatFloat(seconds, 'f', 3, 64)
+}
+
+func junitProperties(config ConfigReport) []junitProperty {
+	return []junitProperty{
+		{"tl", strconv.FormatFloat(config.Tl, 'g', -1, 64)},
+		{"tl_mode", config.TlMode},
+		{"ml", strconv.FormatUint(config.Ml, 10)},
+		{"ol", strconv.FormatUint(config.Ol, 10)},
+		{"prec", strconv.Itoa(int(config.Prec))},
+		{"abs_eps", strconv.FormatFloat(config.AbsEps, 'g', -1, 64)},
+		{"rel_eps", strconv.FormatFloat(config.RelEps, 'g', -1, 64)},
+		{"case", config.Case},
+		{"order", config.Order},
+		{"compare", config.Compare},
+	}
+}
+
+// junitProblemOf describes why the test failed. The message is the verdict's
+// description, and the text contains the details, like the mismatching
+// output and answer.
+func junitProblemOf(test TestReport) *junitProblem {
+	problem := &junitProblem{
+		Message: verdictDescriptions[test.Verdict],
+		Type:    test.Verdict,
+	}
+
+	var text strings.Builder
+
+	switch test.Verdict {
+	case scold.IE.String():
+		fmt.Fprintf(&text, "%s\n", test.Error)
+	case scold.RE.String():
+		fmt.Fprintf(&text, "exit code: %d\n", test.ExitCode)
+	case scold.WA.String():
+		fmt.Fprintf(&text, "Input:\n%s\nAnswer:\n%s\nOutput:\n%s\n", test.Input, test.Answer, test.Stdout)
+	}
+
+	if test.CheckerMessage != "" {
+		fmt.Fprintf(&text, "checker: %s\n", test.CheckerMessage)
+		problem.Message += ": " + test.CheckerMessage
+	}
+
+	problem.Text = text.String()
+	return problem
+}
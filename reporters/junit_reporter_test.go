@@ -0,0 +1,118 @@
+package reporters_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kuredoro/scold"
+	"github.com/kuredoro/scold/reporters"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestJUnitReporter(t *testing.T) {
+	t.Run("tests are mapped to testcases", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := reporters.NewJUnitReporter(&buf, "inputs.txt")
+
+		runReporter(reporter)
+
+		want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="inputs.txt" tests="2" failures="1" errors="0" time="2.000">
+    <properties>
+      <property name="tl" value="2"></property>
+      <property name="tl_mode" value="wall"></property>
+      <property name="ml" value="0"></property>
+      <property name="ol" value="0"></property>
+      <property name="prec" value="6"></property>
+      <property name="abs_eps" value="0"></property>
+      <property name="rel_eps" value="0"></property>
+      <property name="case" value="insensitive"></property>
+      <property name="order" value="strict"></property>
+      <property name="compare" value="tokens"></property>
+    </properties>
+    <testcase name="Test 1" classname="inputs.txt" time="1.500">
+      <system-out>3&#xA;</system-out>
+    </testcase>
+    <testcase name="Test 2" classname="inputs.txt" time="0.500">
+      <failure message="wrong answer" type="WA">Input:&#xA;2 2&#xA;&#xA;Answer:&#xA;four&#xA;&#xA;Output:&#xA;fore&#xA;</failure>
+      <system-out>fore</system-out>
+      <system-err>debug</system-err>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+		td.CmpNoError(t, reporter.Err())
+		scold.AssertText(t, buf.String(), want)
+	})
+
+	t.Run("verdicts are mapped to failures and errors", func(t *testing.T) {
+		inputs := scold.Inputs{
+			Tests: []scold.Test{
+				{Input: "1\n", Output: "1\n"},
+				{Input: "2\n", Output: "2\n"},
+				{Input: "3\n", Output: "3\n"},
+			},
+		}
+
+		re := &scold.TestResult{Verdict: scold.RE}
+		re.ID = 1
+		re.Out.ExitCode = 3
+
+		ie := &scold.TestResult{Verdict: scold.IE}
+		ie.ID = 2
+		ie.Err = errors.New("checker: crashed")
+
+		wa := &scold.TestResult{Verdict: scold.WA, CheckerMessage: "expected 3, found 4"}
+		wa.ID = 3
+
+		var buf bytes.Buffer
+		reporter := reporters.NewJUnitReporter(&buf, "")
+
+		for i, result := range []*scold.TestResult{re, ie, wa} {
+			reporter.TestFinished(&inputs.Tests[i], result)
+		}
+		reporter.SuiteFinished(scold.NewTestingBatch(inputs, nil, nil, nil))
+
+		want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="scold" tests="3" failures="2" errors="1" time="0.000">
+    <properties>
+      <property name="tl" value="0"></property>
+      <property name="tl_mode" value="wall"></property>
+      <property name="ml" value="0"></property>
+      <property name="ol" value="0"></property>
+      <property name="prec" value="0"></property>
+      <property name="abs_eps" value="0"></property>
+      <property name="rel_eps" value="0"></property>
+      <property name="case" value="sensitive"></property>
+      <property name="order" value="strict"></property>
+      <property name="compare" value="tokens"></property>
+    </properties>
+    <testcase name="Test 1" classname="scold" time="0.000">
+      <failure message="runtime error" type="RE">exit code: 3&#xA;</failure>
+    </testcase>
+    <testcase name="Test 2" classname="scold" time="0.000">
+      <error message="internal error" type="IE">checker: crashed&#xA;</error>
+    </testcase>
+    <testcase name="Test 3" classname="scold" time="0.000">
+      <failure message="wrong answer: expected 3, found 4" type="WA">Input:&#xA;3&#xA;&#xA;Answer:&#xA;3&#xA;&#xA;Output:&#xA;&#xA;checker: expected 3, found 4&#xA;</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+		td.CmpNoError(t, reporter.Err())
+		scold.AssertText(t, buf.String(), want)
+	})
+
+	t.Run("errors are reported", func(t *testing.T) {
+		reporter := reporters.NewJUnitReporter(failingWriter{}, "")
+
+		runReporter(reporter)
+
+		td.Cmp(t, reporter.Err(), errWriteFailed)
+	})
+}
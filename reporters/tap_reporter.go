@@ -0,0 +1,189 @@
+package reporters
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kuredoro/scold"
+)
+
+// TAPReporter implements scold.TestingEventListener and writes the results
+// of the tests to W in the TAP version 13 format. The plan line is written
+// before the first result, since the number of tests is known beforehand.
+// The results are written in the order of the test IDs, as TAP requires, so
+// a result is held back until all of the preceding tests finish. The failed
+// tests are followed by a YAML diagnostic block with the verdict, the input,
+// the answer, the output and the exit code.
+type TAPReporter struct {
+	W         io.Writer
+	TestCount int
+
+	started bool
+	next    int
+	pending map[int]TestReport
+	passed  int
+	err     error
+}
+
+// NewTAPReporter creates a TAPReporter for a suite of testCount tests, whose
+// IDs are from 1 to testCount.
+func NewTAPReporter(w io.Writer, testCount int) *TAPReporter {
+	return &TAPReporter{
+		W:         w,
+		TestCount: testCount,
+		next:      1,
+		pending:   make(map[int]TestReport),
+	}
+}
+
+// TestStarted writes the header and the plan if they haven't been written
+// yet.
+func (r *TAPReporter) TestStarted(int) {
+	r.begin()
+}
+
+// TestFinished writes the result of the test and of the tests that finished
+// earlier but had to wait for it.
+func (r *TAPReporter) TestFinished(test *scold.Test, result *scold.TestResult) {
+	r.begin()
+
+	r.pending[result.ID] = NewTestReport(test, result)
+
+	for {
+		report, ok := r.pending[r.next]
+		if !ok {
+			break
+		}
+
+		delete(r.pending, r.next)
+		r.writeResult(report)
+		r.next++
+	}
+}
+
+// SuiteFinished writes the results that are still held back, if any, and
+// a summary comment.
+func (r *TAPReporter) SuiteFinished(*scold.TestingBatch) {
+	r.begin()
+
+	for id := r.next; len(r.pending) != 0; id++ {
+		if report, ok := r.pending[id]; ok {
+			delete(r.pending, id)
+			r.writeResult(report)
+		}
+	}
+
+	r.printf("# %d/%d passed\n", r.passed, r.TestCount)
+}
+
+// Err returns the first error encountered while writing the results.
+func (r *TAPReporter) Err() error {
+	return r.err
+}
+
+func (r *TAPReporter) begin() {
+	if r.started {
+		return
+	}
+
+	r.started = true
+	r.printf("TAP version 13\n1..%d\n", r.TestCount)
+}
+
+func (r *TAPReporter) printf(format string, args ...interface{}) {
+	if r.err != nil {
+		return
+	}
+
+	_, r.err = fmt.Fprintf(r.W, format, args...)
+}
+
+func (r *TAPReporter) writeResult(test TestReport) {
+	if test.Verdict == scold.OK.String() {
+		r.passed++
+		r.printf("ok %d - Test %d\n", test.ID, test.ID)
+		return
+	}
+
+	r.printf("not ok %d - Test %d # %s\n", test.ID, test.ID, test.Verdict)
+
+	var yaml strings.Builder
+	yaml.WriteString("---\n")
+	yaml.WriteString("verdict: " + test.Verdict + "\n")
+	yaml.WriteString("message: " + yamlString(verdictDescriptions[test.Verdict], "  ") + "\n")
+	yaml.WriteString("time: " + strconv.FormatFloat(test.Time, 'f', 3, 64) + "\n")
+	yaml.WriteString("exit_code: " + strconv.Itoa(test.ExitCode) + "\n")
+	yaml.WriteString("input: " + yamlString(test.Input, "  ") + "\n")
+	yaml.WriteString("answer: " + yamlString(test.Answer, "  ") + "\n")
+	yaml.WriteString("output: " + yamlString(test.Stdout, "  ") + "\n")
+
+	if test.Stderr != "" {
+		yaml.WriteString("stderr: " + yamlString(test.Stderr, "  ") + "\n")
+	}
+
+	if test.CheckerMessage != "" {
+		yaml.WriteString("checker_message: " + yamlString(test.CheckerMessage, "  ") + "\n")
+	}
+
+	if test.Error != "" {
+		yaml.WriteString("error: " + yamlString(test.Error, "  ") + "\n")
+	}
+
+	yaml.WriteString("...\n")
+
+	r.printf("%s", indent(yaml.String(), "  "))
+}
+
+// yamlString formats the string as a YAML scalar. The multiline strings are
+// formatted as literal block scalars, whose lines are indented by ind, so
+// that they could be read as is. The other strings, including the ones
+// consisting of LFs only, are double-quoted and escaped.
+func yamlString(str, ind string) string {
+	multiline := strings.Contains(str, "\n") && strings.TrimRight(str, "\n") != ""
+	for _, r := range str {
+		if r != '\n' && r != '\t' && !unicode.IsPrint(r) {
+			multiline = false
+			break
+		}
+	}
+
+	if !multiline {
+		return strconv.Quote(str)
+	}
+
+	header := "|"
+
+	// The indentation cannot be detected if the first line starts with
+	// a space.
+	if strings.HasPrefix(strings.TrimLeft(str, "\n"), " ") {
+		header += strconv.Itoa(len(ind))
+	}
+
+	switch trailing := len(str) - len(strings.TrimRight(str, "\n")); {
+	case trailing == 0:
+		header += "-"
+	case trailing > 1:
+		header += "+"
+	}
+
+	return header + "\n" + strings.TrimSuffix(indent(str, ind), "\n")
+}
+
+// indent prepends ind to each non-empty line of the text.
+func indent(text, ind string) string {
+	lines := strings.SplitAfter(text, "\n")
+
+	var str strings.Builder
+	for _, line := range lines {
+		if line != "" && line != "\n" {
+			str.WriteString(ind)
+		}
+
+		str.WriteString(line)
+	}
+
+	return str.String()
+}
@@ -0,0 +1,93 @@
+package reporters_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kuredoro/scold"
+	"github.com/kuredoro/scold/reporters"
+	"github.com/maxatome/go-testdeep/td"
+)
+
+func TestTAPReporter(t *testing.T) {
+	t.Run("results are written in the order of IDs", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := reporters.NewTAPReporter(&buf, len(reportedInputs.Tests))
+
+		runReporter(reporter)
+
+		want := `TAP version 13
+1..2
+ok 1 - Test 1
+not ok 2 - Test 2 # WA
+  ---
+  verdict: WA
+  message: "wrong answer"
+  time: 0.500
+  exit_code: 0
+  input: |
+    2 2
+  answer: |
+    four
+  output: "fore"
+  stderr: "debug"
+  ...
+# 1/2 passed
+`
+
+		td.CmpNoError(t, reporter.Err())
+		scold.AssertText(t, buf.String(), want)
+	})
+
+	t.Run("plan is written before the first result", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := reporters.NewTAPReporter(&buf, 3)
+
+		reporter.TestStarted(1)
+
+		scold.AssertText(t, buf.String(), "TAP version 13\n1..3\n")
+	})
+
+	t.Run("tricky strings are valid YAML", func(t *testing.T) {
+		inputs := scold.Inputs{
+			Tests: []scold.Test{
+				{Input: "  indented\nlines\n\n", Output: "\n"},
+			},
+		}
+
+		result := &scold.TestResult{Verdict: scold.RE}
+		result.ID = 1
+		result.Out = scold.ExecutionResult{ExitCode: 1, Stdout: "a\r\nb", Stderr: "panic: oops\n\ngoroutine 1"}
+
+		var buf bytes.Buffer
+		reporter := reporters.NewTAPReporter(&buf, 1)
+
+		reporter.TestFinished(&inputs.Tests[0], result)
+		reporter.SuiteFinished(nil)
+
+		want := `TAP version 13
+1..1
+not ok 1 - Test 1 # RE
+  ---
+  verdict: RE
+  message: "runtime error"
+  time: 0.000
+  exit_code: 1
+  input: |2+
+      indented
+    lines
+
+  answer: "\n"
+  output: "a\r\nb"
+  stderr: |-
+    panic: oops
+
+    goroutine 1
+  ...
+# 0/1 passed
+`
+
+		td.CmpNoError(t, reporter.Err())
+		scold.AssertText(t, buf.String(), want)
+	})
+}
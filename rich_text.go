@@ -1,7 +1,10 @@
 package scold
 
 import (
+	"fmt"
+	"html"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/logrusorgru/aurora"
 )
@@ -18,6 +21,8 @@ func init() {
 // RichText represents a text data with additional color metadata in a form
 // of a bitmask. The characters may be either colored or uncolored. The _color_
 // might represent a literal color or a formatting style like bold or italics.
+// The mask has an element for each rune of Str, so that a multibyte
+// character is either colored or not as a whole.
 type RichText struct {
 	Str  string
 	Mask []bool
@@ -37,27 +42,54 @@ func (rt RichText) Colorful() bool {
 // Colorize returns Str with ASCII escape codes actually
 // embedded inside it to enable colors. The resulting string then
 // can be printed on the screen and it'll be colorful, for example.
+// Only the runes that have a corresponding mask element are output.
 func (rt RichText) Colorize(color aurora.Color) string {
 	var str strings.Builder
 
-	start := 0
-	for start != len(rt.Mask) {
-		end := start + 1
-		for ; end != len(rt.Mask); end++ {
-			if rt.Mask[start] != rt.Mask[end] {
-				break
-			}
-		}
-
-		part := rt.Str[start:end]
-		if rt.Mask[start] {
+	rt.forEachPart(func(part string, colored bool) {
+		if colored {
 			str.WriteString(Au.Colorize(part, color).String())
 		} else {
 			str.WriteString(part)
 		}
+	})
 
-		start = end
-	}
+	return str.String()
+}
+
+// HTML returns Str escaped for embedding into HTML, with the colored parts
+// wrapped in span elements of the given class. Like Colorize, only the
+// runes that have a corresponding mask element are output.
+func (rt RichText) HTML(class string) string {
+	var str strings.Builder
+
+	rt.forEachPart(func(part string, colored bool) {
+		if colored {
+			fmt.Fprintf(&str, `<span class="%s">%s</span>`, html.EscapeString(class), html.EscapeString(part))
+		} else {
+			str.WriteString(html.EscapeString(part))
+		}
+	})
 
 	return str.String()
 }
+
+// forEachPart splits the masked part of Str into the longest parts that are
+// either colored or not as a whole.
+func (rt RichText) forEachPart(f func(part string, colored bool)) {
+	start, runeIndex := 0, 0
+	for start != len(rt.Str) && runeIndex != len(rt.Mask) {
+		colored := rt.Mask[runeIndex]
+
+		end := start
+		for end != len(rt.Str) && runeIndex != len(rt.Mask) && rt.Mask[runeIndex] == colored {
+			_, width := utf8.DecodeRuneInString(rt.Str[end:])
+			end += width
+			runeIndex++
+		}
+
+		f(rt.Str[start:end], colored)
+
+		start = end
+	}
+}
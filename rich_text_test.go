@@ -74,6 +74,85 @@ func TestRichTextColorize(t *testing.T) {
 			t.Errorf("got rich text '%s', want '%s'", got, want)
 		}
 	})
+	t.Run("multibyte runes are colored as a whole", func(t *testing.T) {
+		rt := scold.RichText{
+			"привет", []bool{false, false, false, false, true, true},
+		}
+
+		got := rt.Colorize(aurora.BoldFm)
+		want := fmt.Sprint("прив", aurora.Bold("ет"))
+
+		if got != want {
+			t.Errorf("got rich text '%s', want '%s'", got, want)
+		}
+	})
+
+	t.Run("runes of different widths", func(t *testing.T) {
+		rt := scold.RichText{
+			"a日éb", []bool{false, true, true, false},
+		}
+
+		got := rt.Colorize(aurora.BoldFm)
+		want := fmt.Sprint("a", aurora.Bold("日é"), "b")
+
+		if got != want {
+			t.Errorf("got rich text '%s', want '%s'", got, want)
+		}
+	})
+
+	t.Run("the mask may crop a multibyte string", func(t *testing.T) {
+		rt := scold.RichText{
+			"日本語", make([]bool, 2),
+		}
+
+		got := rt.Colorize(0)
+		want := "日本"
+
+		if got != want {
+			t.Errorf("got rich text %q, want %q", got, want)
+		}
+	})
+}
+
+func TestRichTextHTML(t *testing.T) {
+	t.Run("colored parts are wrapped in spans", func(t *testing.T) {
+		rt := scold.RichText{
+			"abcdef", []bool{false, true, true, false, false, true},
+		}
+
+		got := rt.HTML("hl")
+		want := `a<span class="hl">bc</span>de<span class="hl">f</span>`
+
+		if got != want {
+			t.Errorf("got rich text %q, want %q", got, want)
+		}
+	})
+
+	t.Run("special characters are escaped", func(t *testing.T) {
+		rt := scold.RichText{
+			"<a&b>", []bool{true, false, false, false, true},
+		}
+
+		got := rt.HTML("hl")
+		want := `<span class="hl">&lt;</span>a&amp;b<span class="hl">&gt;</span>`
+
+		if got != want {
+			t.Errorf("got rich text %q, want %q", got, want)
+		}
+	})
+
+	t.Run("the mask may crop the string", func(t *testing.T) {
+		rt := scold.RichText{
+			"привет", []bool{false, true},
+		}
+
+		got := rt.HTML("hl")
+		want := `п<span class="hl">р</span>`
+
+		if got != want {
+			t.Errorf("got rich text %q, want %q", got, want)
+		}
+	})
 }
 
 func TestRichTextColorful(t *testing.T) {
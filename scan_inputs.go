@@ -2,7 +2,9 @@ package scold
 
 import (
 	"bufio"
+	"reflect"
 	"strings"
+	"unicode"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/stoewer/go-strcase"
@@ -12,12 +14,15 @@ import (
 const (
 	IOSeparatorMissing = StringError("IO separator missing")
 	KeyMissing         = StringError("key cannot be empty")
+	SuiteOnlyOption    = StringWarning("option can be specified only for the whole test suite")
 )
 
 // The set of delimeters used when partitioning inputs file.
 const (
-	IODelim   = "---"
-	TestDelim = "==="
+	IODelim        = "---"
+	TestDelim      = "==="
+	ConfigDelim    = "+++"
+	AltAnswerDelim = "---or---"
 )
 
 // DefaultInputsConfig is used to define default values for the InputsConfig
@@ -26,16 +31,46 @@ const (
 var DefaultInputsConfig InputsConfig
 
 // Test represents a single test case: an input and the expected output.
+// If the output may be different, the alternatives to Output are listed in
+// AltOutputs. If the test overrides some of the suite's options, Config
+// holds the suite's config with the overrides applied. Otherwise, it's nil.
 type Test struct {
-	Input  string
-	Output string
+	Input      string
+	Output     string
+	AltOutputs []string
+
+	Config *InputsConfig
+}
+
+// Answers returns all of the accepted outputs, Output being the first.
+func (t *Test) Answers() []string {
+	answers := make([]string, 0, 1+len(t.AltOutputs))
+	answers = append(answers, t.Output)
+	return append(answers, t.AltOutputs...)
 }
 
 // InputsConfig defines a schema for available configuration options that
 // can be listed inside a config.
 type InputsConfig struct {
-	Prec uint8
-	Tl   PositiveDuration
+	Prec    uint8
+	Tl      PositiveDuration
+	TlMode  TimeLimitMode
+	Ml      ByteSize
+	Ol      ByteSize
+	AbsEps  float64
+	RelEps  float64
+	Case    CaseMode
+	Order   OrderMode
+	Compare CompareMode
+}
+
+// testConfigOverrides lists the options that can be overridden for
+// a single test.
+type testConfigOverrides struct {
+	Tl     PositiveDuration
+	Prec   uint8
+	AbsEps float64
+	RelEps float64
 }
 
 // Inputs contains all information located in the inputs file: tests and
@@ -127,13 +162,22 @@ func ScanConfig(text string) (config map[string]string, key2line map[string]Numb
 // User can specify the number of parts they want at most via the third
 // argument.
 func SplitByInlinedPrefixN(text, delim string, n int) (parts []string) {
+	return splitByLinesN(text, n, func(line string) bool {
+		return strings.HasPrefix(line, delim)
+	})
+}
+
+// splitByLinesN splits text into at most n parts (or any number of parts, if
+// n is zero) at the lines for which isDelim returns true. The delimiting lines
+// are discarded.
+func splitByLinesN(text string, n int, isDelim func(line string) bool) (parts []string) {
 
 	var str strings.Builder
 
 	s := bufio.NewScanner(strings.NewReader(text))
 	for s.Scan() {
 
-		if (n == 0 || len(parts)+1 < n) && strings.HasPrefix(s.Text(), delim) {
+		if (n == 0 || len(parts)+1 < n) && isDelim(s.Text()) {
 			part := str.String()
 			parts = append(parts, part)
 
@@ -151,22 +195,39 @@ func SplitByInlinedPrefixN(text, delim string, n int) (parts []string) {
 	return
 }
 
+func isAltAnswerDelim(line string) bool {
+	return strings.TrimRightFunc(line, unicode.IsSpace) == AltAnswerDelim
+}
+
 // ScanTest parses a single test case: input and output, separated with the
-// Input/Output separator. If separator is absent, it returns an error.
+// Input/Output separator. If separator is absent, it returns an error. The
+// output may be followed by alternative outputs, each preceded by the line
+// that is exactly AltAnswerDelim.
 func ScanTest(testStr string) (Test, []error) {
 	if strings.TrimSpace(testStr) == "" {
 		return Test{}, nil
 	}
 
-	parts := SplitByInlinedPrefixN(testStr, IODelim, 2)
+	// AltAnswerDelim starts with IODelim, so it has to be told apart
+	// explicitly. Unlike IODelim, it must occupy the whole line.
+	parts := splitByLinesN(testStr, 2, func(line string) bool {
+		return strings.HasPrefix(line, IODelim) && !isAltAnswerDelim(line)
+	})
 
 	if len(parts) == 1 {
 		return Test{}, []error{IOSeparatorMissing}
 	}
 
+	answers := splitByLinesN(parts[1], 0, isAltAnswerDelim)
+
 	test := Test{
-		Input:  parts[0],
-		Output: parts[1],
+		Input:      parts[0],
+		Output:     answers[0],
+		AltOutputs: answers[1:],
+	}
+
+	if len(test.AltOutputs) == 0 {
+		test.AltOutputs = nil
 	}
 
 	return test, nil
@@ -180,6 +241,11 @@ func ScanTest(testStr string) (Test, []error) {
 // non-printable characters and that don't contain IO separator). If a test case
 // could not be parsed, parsing continues to the next test case, but the errors
 // are accumulated and returned together.
+//
+// Each test case may start with a header of key-value pairs terminated by
+// the ConfigDelim line, which overrides the suite's options for this test
+// only. Only the time limit and the options that control the comparison of
+// floats can be overridden.
 func ScanInputs(text string) (inputs Inputs, errs []error) {
 	inputs.Config = DefaultInputsConfig
 
@@ -201,31 +267,15 @@ func ScanInputs(text string) (inputs Inputs, errs []error) {
 
 		// Try to parse config
 		if testErrs != nil && partNum == 0 {
-			config, key2line, configErrs := ScanConfig(part)
-
-			if configErrs != nil {
-				errs = append(errs, configErrs...)
-				// We don't stop because ScanConfig gathers only correct
-				// key-value pairs.
-			}
-
-			unmarshalErrs := StringMapUnmarshal(config, &inputs.Config, strcase.UpperCamelCase)
-			if unmarshalErrs != nil {
-				merr := unmarshalErrs.(*multierror.Error)
-				for i, err := range merr.Errors {
-					numberedLine := key2line[err.(*FieldError).FieldName]
-					merr.Errors[i] = &LineRangeError{
-						Begin: numberedLine.Num,
-						Lines: []string{numberedLine.Line},
-						Err:   err,
-					}
-				}
-				errs = append(errs, merr.Errors...)
-			}
-
+			errs = append(errs, scanSuiteConfig(part, &inputs.Config)...)
 			continue
 		}
 
+		header, body, hasHeader := splitTestHeader(part)
+		if hasHeader {
+			test, testErrs = ScanTest(body)
+		}
+
 		// Skip empty tests
 		if testErrs == nil && test.Input == "" && test.Output == "" {
 			continue
@@ -252,8 +302,124 @@ func ScanInputs(text string) (inputs Inputs, errs []error) {
 			continue
 		}
 
+		if hasHeader {
+			config, key2line, configErrs := ScanConfig(header)
+			for _, err := range configErrs {
+				err.(*LineRangeError).Begin += lineNum - 1
+			}
+			errs = append(errs, configErrs...)
+
+			overrides := testConfigOverrides{
+				Tl:     inputs.Config.Tl,
+				Prec:   inputs.Config.Prec,
+				AbsEps: inputs.Config.AbsEps,
+				RelEps: inputs.Config.RelEps,
+			}
+
+			unmarshalErrs := StringMapUnmarshal(config, &overrides, strcase.UpperCamelCase)
+			markSuiteOnlyOptions(unmarshalErrs)
+			errs = append(errs, configLineErrors(unmarshalErrs, key2line, lineNum-1)...)
+
+			testConfig := inputs.Config
+			testConfig.Tl = overrides.Tl
+			testConfig.Prec = overrides.Prec
+			testConfig.AbsEps = overrides.AbsEps
+			testConfig.RelEps = overrides.RelEps
+			test.Config = &testConfig
+		}
+
 		inputs.Tests = append(inputs.Tests, test)
 	}
 
 	return
 }
+
+// scanSuiteConfig parses the suite's options from text into config. The
+// options that are not specified are left as is.
+func scanSuiteConfig(text string, config *InputsConfig) (errs []error) {
+	options, key2line, configErrs := ScanConfig(text)
+
+	// We don't stop because ScanConfig gathers only correct key-value
+	// pairs.
+	errs = append(errs, configErrs...)
+
+	unmarshalErrs := StringMapUnmarshal(options, config, strcase.UpperCamelCase)
+	return append(errs, configLineErrors(unmarshalErrs, key2line, 0)...)
+}
+
+// splitTestHeader separates the config header from the rest of the test.
+// The header must be terminated by ConfigDelim before the IODelim. Since the
+// test's input may contain the ConfigDelim line itself, the lines above it
+// are considered a header only if they look like a config.
+func splitTestHeader(part string) (header, body string, ok bool) {
+	parts := SplitByInlinedPrefixN(part, ConfigDelim, 2)
+	if len(parts) == 1 || len(SplitByInlinedPrefixN(parts[0], IODelim, 2)) != 1 {
+		return "", part, false
+	}
+
+	if !looksLikeConfig(parts[0]) {
+		return "", part, false
+	}
+
+	return parts[0], parts[1], true
+}
+
+// looksLikeConfig tells whether the text has at least one key-value pair and
+// every non-empty line of it is a key-value pair.
+func looksLikeConfig(text string) bool {
+	pairs := 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		_, _, err := ScanKeyValuePair(line)
+		if err != nil || !strings.Contains(line, "=") {
+			return false
+		}
+
+		pairs++
+	}
+
+	return pairs != 0
+}
+
+// configLineErrors attributes the errors returned by StringMapUnmarshal to
+// the lines of the config, whose first line is the line after lineOffset.
+func configLineErrors(unmarshalErrs error, key2line map[string]NumberedLine, lineOffset int) []error {
+	if unmarshalErrs == nil {
+		return nil
+	}
+
+	merr := unmarshalErrs.(*multierror.Error)
+	for i, err := range merr.Errors {
+		numberedLine := key2line[err.(*FieldError).FieldName]
+		merr.Errors[i] = &LineRangeError{
+			Begin: lineOffset + numberedLine.Num,
+			Lines: []string{numberedLine.Line},
+			Err:   err,
+		}
+	}
+
+	return merr.Errors
+}
+
+// markSuiteOnlyOptions replaces ErrUnknownField with SuiteOnlyOption for the
+// options that a test's config doesn't have, but the suite's one does.
+func markSuiteOnlyOptions(unmarshalErrs error) {
+	if unmarshalErrs == nil {
+		return
+	}
+
+	suiteConfig := reflect.TypeOf(InputsConfig{})
+
+	merr := unmarshalErrs.(*multierror.Error)
+	for i, err := range merr.Errors {
+		fieldErr := err.(*FieldError)
+
+		_, isSuiteOption := suiteConfig.FieldByName(strcase.UpperCamelCase(fieldErr.FieldName))
+		if fieldErr.Err == ErrUnknownField && isSuiteOption {
+			merr.Errors[i] = &FieldError{fieldErr.FieldName, SuiteOnlyOption}
+		}
+	}
+}
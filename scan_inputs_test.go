@@ -2,6 +2,7 @@ package scold_test
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -78,6 +79,63 @@ c`
 			scold.AssertNoErrors(t, errs)
 		})
 
+	t.Run("alternative answers",
+		func(t *testing.T) {
+			text := `a
+---
+b
+---or---
+c
+---
+---or---
+d`
+
+			text = strings.ReplaceAll(text, "---or---", scold.AltAnswerDelim)
+
+			want := scold.Test{
+				Input:      "a\n",
+				Output:     "b\n",
+				AltOutputs: []string{"c\n---\n", "d\n"},
+			}
+
+			test, errs := scold.ScanTest(text)
+
+			scold.AssertTest(t, test, want)
+			scold.AssertNoErrors(t, errs)
+
+			answersWant := []string{"b\n", "c\n---\n", "d\n"}
+			if got := test.Answers(); !reflect.DeepEqual(got, answersWant) {
+				t.Errorf("got answers %q, want %q", got, answersWant)
+			}
+		})
+
+	t.Run("alternative answer delimeter must match the whole line",
+		func(t *testing.T) {
+			text := `a
+---or---
+b
+---
+c
+---or---x
+d
+---or---
+---
+e`
+
+			text = strings.ReplaceAll(text, "---or---", scold.AltAnswerDelim)
+
+			want := scold.Test{
+				Input:      "a\n---or---\nb\n",
+				Output:     "c\n---or---x\nd\n",
+				AltOutputs: []string{"---\ne\n"},
+			}
+
+			test, errs := scold.ScanTest(text)
+
+			scold.AssertTest(t, test, want)
+			scold.AssertNoErrors(t, errs)
+		})
+
 	t.Run("only the prefix of a line should match IO delimeter",
 		func(t *testing.T) {
 			inputText := "3\r\n" +
@@ -380,6 +438,155 @@ prec= 16
 			td.Cmp(t, inputs.Config, configWant)
 		})
 
+	t.Run("memory and output limits are parsed with units",
+		func(t *testing.T) {
+			configWant := scold.InputsConfig{
+				Tl:   scold.DefaultInputsConfig.Tl,
+				Prec: scold.DefaultInputsConfig.Prec,
+				Ml:   256 * scold.Megabyte,
+				Ol:   16 * scold.Kilobyte,
+			}
+
+			text := `
+ml = 256MB
+ol = 16KB
+===
+2 2
+---
+4
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			scold.AssertNoErrors(t, errs)
+			td.Cmp(t, inputs.Config, configWant)
+		})
+
+	t.Run("time limit mode is parsed",
+		func(t *testing.T) {
+			configWant := scold.InputsConfig{
+				Tl:     scold.PositiveDuration{2 * time.Second},
+				TlMode: scold.CPUTimeMode,
+				Prec:   scold.DefaultInputsConfig.Prec,
+			}
+
+			text := `
+tl = 2s
+tl_mode = cpu
+===
+2 2
+---
+4
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			scold.AssertNoErrors(t, errs)
+			td.Cmp(t, inputs.Config, configWant)
+		})
+
+	t.Run("float tolerances are parsed",
+		func(t *testing.T) {
+			configWant := scold.InputsConfig{
+				Tl:     scold.DefaultInputsConfig.Tl,
+				Prec:   scold.DefaultInputsConfig.Prec,
+				AbsEps: 1e-6,
+				RelEps: 0.001,
+			}
+
+			text := `
+abs_eps = 1e-6
+rel_eps = 0.001
+===
+2 2
+---
+4
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			scold.AssertNoErrors(t, errs)
+			td.Cmp(t, inputs.Config, configWant)
+		})
+
+	t.Run("case mode is parsed",
+		func(t *testing.T) {
+			configWant := scold.InputsConfig{
+				Tl:   scold.DefaultInputsConfig.Tl,
+				Prec: scold.DefaultInputsConfig.Prec,
+				Case: scold.CaseInsensitive,
+			}
+
+			text := `
+case = insensitive
+===
+yes
+---
+YES
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			scold.AssertNoErrors(t, errs)
+			td.Cmp(t, inputs.Config, configWant)
+		})
+
+	t.Run("order mode is parsed",
+		func(t *testing.T) {
+			configWant := scold.InputsConfig{
+				Tl:    scold.DefaultInputsConfig.Tl,
+				Prec:  scold.DefaultInputsConfig.Prec,
+				Order: scold.AnyLineOrder,
+			}
+
+			text := `
+order = any_lines
+===
+1 2
+---
+1 2
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			scold.AssertNoErrors(t, errs)
+			td.Cmp(t, inputs.Config, configWant)
+		})
+
+	t.Run("compare mode is parsed",
+		func(t *testing.T) {
+			configWant := scold.InputsConfig{
+				Tl:      scold.DefaultInputsConfig.Tl,
+				Prec:    scold.DefaultInputsConfig.Prec,
+				Compare: scold.CompareExact,
+			}
+
+			text := `
+compare = exact
+===
+1
+---
+#
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			scold.AssertNoErrors(t, errs)
+			td.Cmp(t, inputs.Config, configWant)
+		})
+
 	t.Run("not listed config keys shall be set to default",
 		func(t *testing.T) {
 			testsWant := []scold.Test{
@@ -496,6 +703,117 @@ by the way...
 			scold.AssertNoErrors(t, errs)
 			scold.AssertDefaultConfig(t, inputs.Config)
 		})
+
+	t.Run("tests may override options in a header",
+		func(t *testing.T) {
+			suiteConfig := scold.InputsConfig{
+				Tl:   scold.PositiveDuration{2 * time.Second},
+				Prec: 4,
+			}
+
+			testsWant := []scold.Test{
+				{
+					Input:  "1\n",
+					Output: "1\n",
+				},
+				{
+					Input:  "+++\n",
+					Output: "+++\n",
+					Config: &scold.InputsConfig{
+						Tl:   scold.PositiveDuration{10 * time.Second},
+						Prec: 8,
+					},
+				},
+				{
+					Input:  "3\n",
+					Output: "3\n",
+					Config: &suiteConfig,
+				},
+			}
+
+			text := `
+tl = 2s
+prec = 4
+===
+1
+---
+1
+===
+tl = 10s
+prec = 8
++++
++++
+---
++++
+===
+ml = 1GB
+tl = 1bs
++++
+3
+---
+3
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+			text = strings.ReplaceAll(text, "+++", scold.ConfigDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			errsWant := []error{
+				&scold.LineRangeError{16, []string{"ml = 1GB"}, &scold.FieldError{"ml", scold.SuiteOnlyOption}},
+				&scold.LineRangeError{17, []string{"tl = 1bs"}, &scold.FieldError{"tl", &scold.NotValueOfTypeError{"PositiveDuration", "1bs", scold.ErrDurationBadSyntax}}},
+			}
+
+			scold.AssertTests(t, inputs.Tests, testsWant)
+			td.Cmp(t, errs, td.Bag(td.Flatten(errsWant)))
+			td.Cmp(t, inputs.Config, suiteConfig)
+		})
+
+	t.Run("config delimiter below non-config lines is the test's text",
+		func(t *testing.T) {
+			testsWant := []scold.Test{
+				{
+					Input:  "1 2\n+++\n3\n",
+					Output: "6\n",
+				},
+				{
+					Input:  "+++\n",
+					Output: "+++\n",
+				},
+				{
+					Input:  "a = b\nc\n+++\n",
+					Output: "1\n",
+				},
+			}
+
+			text := `
+===
+1 2
++++
+3
+---
+6
+===
++++
+---
++++
+===
+a = b
+c
++++
+---
+1
+`
+			text = strings.ReplaceAll(text, "---", scold.IODelim)
+			text = strings.ReplaceAll(text, "===", scold.TestDelim)
+			text = strings.ReplaceAll(text, "+++", scold.ConfigDelim)
+
+			inputs, errs := scold.ScanInputs(text)
+
+			scold.AssertNoErrors(t, errs)
+			scold.AssertTests(t, inputs.Tests, testsWant)
+			scold.AssertDefaultConfig(t, inputs.Config)
+		})
 }
 
 func TestScanConfig(t *testing.T) {
@@ -1,6 +1,7 @@
 package scold
 
 import (
+	"context"
 	"time"
 
 	"github.com/jonboulle/clockwork"
@@ -15,6 +16,14 @@ type Stopwatcher interface {
 	TimeLimit(since time.Time) <-chan time.Time
 }
 
+// TimeLimitOverrider is implemented by the Stopwatchers that support the
+// tests overriding the time limit. WithTimeLimit returns the same stopwatch,
+// but with a different time limit. TestingBatch uses it for the tests that
+// override the time limit, if Swatch implements it.
+type TimeLimitOverrider interface {
+	WithTimeLimit(tl time.Duration) Stopwatcher
+}
+
 // ConfigurableStopwatcher implements Stopwatcher and allows its user to
 // fully customize it with no make function required.
 type ConfigurableStopwatcher struct {
@@ -49,3 +58,26 @@ func (s *ConfigurableStopwatcher) TimeLimit(since time.Time) <-chan time.Time {
 
 	return s.Clock.After(since.Add(s.TL).Sub(s.Clock.Now()))
 }
+
+// WithTimeLimit returns a copy of the stopwatcher with TL set to tl.
+func (s *ConfigurableStopwatcher) WithTimeLimit(tl time.Duration) Stopwatcher {
+	copy := *s
+	copy.TL = tl
+	return &copy
+}
+
+type timeLimitKey struct{}
+
+// ContextWithTimeLimit returns a copy of ctx that carries the time limit of
+// the test. TestingBatch uses it to tell Processer about the tests whose
+// time limit is overridden.
+func ContextWithTimeLimit(ctx context.Context, tl time.Duration) context.Context {
+	return context.WithValue(ctx, timeLimitKey{}, tl)
+}
+
+// TimeLimitFromContext returns the time limit stored in ctx by
+// ContextWithTimeLimit, if any.
+func TimeLimitFromContext(ctx context.Context) (time.Duration, bool) {
+	tl, ok := ctx.Value(timeLimitKey{}).(time.Duration)
+	return tl, ok
+}
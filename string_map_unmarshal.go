@@ -3,8 +3,10 @@ package scold
 import (
 	"encoding"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -31,6 +33,26 @@ const (
 	// ErrDurationBadSyntax is issued when PositiveDuration is unmarshalled
 	// with a value that cannot be interpreted as a duration.
 	ErrDurationBadSyntax = StringError("bad syntax. Correct values could be \"1s\" or \"12.3ms\"")
+
+	// ErrByteSizeBadSyntax is issued when ByteSize is unmarshalled with
+	// a value that cannot be interpreted as an amount of memory.
+	ErrByteSizeBadSyntax = StringError("bad syntax. Correct values could be \"256MB\" or \"1.5GB\"")
+
+	// ErrTimeLimitModeBadSyntax is issued when TimeLimitMode is unmarshalled
+	// with a value other than "wall" or "cpu".
+	ErrTimeLimitModeBadSyntax = StringError("unknown mode. Correct values are \"wall\" and \"cpu\"")
+
+	// ErrCaseModeBadSyntax is issued when CaseMode is unmarshalled with
+	// a value other than "sensitive" or "insensitive".
+	ErrCaseModeBadSyntax = StringError("unknown mode. Correct values are \"sensitive\" and \"insensitive\"")
+
+	// ErrOrderModeBadSyntax is issued when OrderMode is unmarshalled with
+	// a value other than "strict", "any_tokens" or "any_lines".
+	ErrOrderModeBadSyntax = StringError("unknown mode. Correct values are \"strict\", \"any_tokens\" and \"any_lines\"")
+
+	// ErrCompareModeBadSyntax is issued when CompareMode is unmarshalled with
+	// a value other than "tokens", "lines" or "exact".
+	ErrCompareModeBadSyntax = StringError("unknown mode. Correct values are \"tokens\", \"lines\" and \"exact\"")
 )
 
 var intParsers = map[reflect.Kind]int{
@@ -102,6 +124,213 @@ func (d *PositiveDuration) UnmarshalText(b []byte) error {
 	return nil
 }
 
+// ByteSize represents an amount of memory in bytes. It allows
+// StringMapUnmarshal to parse it from a string with a unit suffix.
+// Implements encoding.TextUnmarshaler.
+type ByteSize uint64
+
+// Units of ByteSize. As is customary in competitive programming, the
+// units are powers of 1024.
+const (
+	Byte     ByteSize = 1
+	Kilobyte          = 1024 * Byte
+	Megabyte          = 1024 * Kilobyte
+	Gigabyte          = 1024 * Megabyte
+)
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"GB", Gigabyte},
+	{"MB", Megabyte},
+	{"KB", Kilobyte},
+	{"B", Byte},
+}
+
+// UnmarshalText accepts a non-negative number followed by one of the units:
+// "B", "KB", "MB" or "GB", case-insensitively. The number may have
+// a fractional part, like "1.5GB".
+func (s *ByteSize) UnmarshalText(b []byte) error {
+	text := strings.ToUpper(strings.TrimSpace(string(b)))
+
+	for _, unit := range byteSizeUnits {
+		if !strings.HasSuffix(text, unit.suffix) {
+			continue
+		}
+
+		numText := strings.TrimSpace(strings.TrimSuffix(text, unit.suffix))
+
+		num, err := strconv.ParseFloat(numText, 64)
+		if err != nil || num < 0 || math.IsInf(num, 0) || math.IsNaN(num) {
+			return ErrByteSizeBadSyntax
+		}
+
+		*s = ByteSize(num * float64(unit.size))
+		return nil
+	}
+
+	return ErrByteSizeBadSyntax
+}
+
+// String renders the size in the largest unit that fits it, e.g., "12.4MB".
+func (s ByteSize) String() string {
+	for _, unit := range byteSizeUnits {
+		if s >= unit.size && unit.size != Byte {
+			return fmt.Sprintf("%.1f%s", float64(s)/float64(unit.size), unit.suffix)
+		}
+	}
+
+	return fmt.Sprintf("%dB", uint64(s))
+}
+
+// TimeLimitMode tells what kind of time the time limit restricts.
+// Implements encoding.TextUnmarshaler.
+type TimeLimitMode uint8
+
+// The time limit modes. WallTimeMode limits the real time elapsed since the
+// start of the process, while CPUTimeMode limits the CPU time the process
+// consumed, which is not affected by the load of the machine.
+const (
+	WallTimeMode TimeLimitMode = iota
+	CPUTimeMode
+)
+
+// UnmarshalText accepts either "wall" or "cpu".
+func (m *TimeLimitMode) UnmarshalText(b []byte) error {
+	switch strings.TrimSpace(string(b)) {
+	case "wall":
+		*m = WallTimeMode
+	case "cpu":
+		*m = CPUTimeMode
+	default:
+		return ErrTimeLimitModeBadSyntax
+	}
+
+	return nil
+}
+
+func (m TimeLimitMode) String() string {
+	if m == CPUTimeMode {
+		return "cpu"
+	}
+
+	return "wall"
+}
+
+// CaseMode tells whether the letter case matters when comparing string
+// lexemes. Implements encoding.TextUnmarshaler.
+type CaseMode uint8
+
+// The case modes.
+const (
+	CaseSensitive CaseMode = iota
+	CaseInsensitive
+)
+
+// UnmarshalText accepts either "sensitive" or "insensitive".
+func (m *CaseMode) UnmarshalText(b []byte) error {
+	switch strings.TrimSpace(string(b)) {
+	case "sensitive":
+		*m = CaseSensitive
+	case "insensitive":
+		*m = CaseInsensitive
+	default:
+		return ErrCaseModeBadSyntax
+	}
+
+	return nil
+}
+
+func (m CaseMode) String() string {
+	if m == CaseInsensitive {
+		return "insensitive"
+	}
+
+	return "sensitive"
+}
+
+// OrderMode tells whether the order of the lexemes in the output matters.
+// Implements encoding.TextUnmarshaler.
+type OrderMode uint8
+
+// The order modes. In StrictOrder, the lexemes are compared position by
+// position. In AnyTokenOrder, the output may be any permutation of the
+// answer's lexemes, and in AnyLineOrder, any permutation of its lines.
+const (
+	StrictOrder OrderMode = iota
+	AnyTokenOrder
+	AnyLineOrder
+)
+
+// UnmarshalText accepts "strict", "any_tokens" or "any_lines".
+func (m *OrderMode) UnmarshalText(b []byte) error {
+	switch strings.TrimSpace(string(b)) {
+	case "strict":
+		*m = StrictOrder
+	case "any_tokens":
+		*m = AnyTokenOrder
+	case "any_lines":
+		*m = AnyLineOrder
+	default:
+		return ErrOrderModeBadSyntax
+	}
+
+	return nil
+}
+
+func (m OrderMode) String() string {
+	switch m {
+	case AnyTokenOrder:
+		return "any_tokens"
+	case AnyLineOrder:
+		return "any_lines"
+	}
+
+	return "strict"
+}
+
+// CompareMode tells what the output is split into before the comparison.
+// Implements encoding.TextUnmarshaler.
+type CompareMode uint8
+
+// The compare modes. In CompareTokens, the output is split into lexemes
+// separated by whitespace. In CompareLines, the output is split into lines
+// that are compared as strings after removing the trailing whitespace, and
+// in CompareExact, the output must match the answer byte by byte.
+const (
+	CompareTokens CompareMode = iota
+	CompareLines
+	CompareExact
+)
+
+// UnmarshalText accepts "tokens", "lines" or "exact".
+func (m *CompareMode) UnmarshalText(b []byte) error {
+	switch strings.TrimSpace(string(b)) {
+	case "tokens":
+		*m = CompareTokens
+	case "lines":
+		*m = CompareLines
+	case "exact":
+		*m = CompareExact
+	default:
+		return ErrCompareModeBadSyntax
+	}
+
+	return nil
+}
+
+func (m CompareMode) String() string {
+	switch m {
+	case CompareLines:
+		return "lines"
+	case CompareExact:
+		return "exact"
+	}
+
+	return "tokens"
+}
+
 // StringMapUnmarshal accepts a string map and for each key-value pair tries
 // to find an identically named field in the provided object, parse the
 // string value according to the field's type and assign the parsed value
@@ -704,3 +704,172 @@ func TestPositiveDuration(t *testing.T) {
 		td.Cmp(t, err, scold.ErrDurationBadSyntax)
 	})
 }
+
+func TestByteSize(t *testing.T) {
+	t.Run("megabytes", func(t *testing.T) {
+		var size scold.ByteSize
+		err := size.UnmarshalText([]byte("256MB"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, size, 256*scold.Megabyte)
+	})
+
+	t.Run("fractional gigabytes", func(t *testing.T) {
+		var size scold.ByteSize
+		err := size.UnmarshalText([]byte("1.5GB"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, size, 1536*scold.Megabyte)
+	})
+
+	t.Run("units are case-insensitive", func(t *testing.T) {
+		var size scold.ByteSize
+		err := size.UnmarshalText([]byte("64kb"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, size, 64*scold.Kilobyte)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		var size scold.ByteSize
+		err := size.UnmarshalText([]byte("100B"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, size, 100*scold.Byte)
+	})
+
+	t.Run("number without unit is forbidden", func(t *testing.T) {
+		var size scold.ByteSize
+		err := size.UnmarshalText([]byte("256"))
+
+		td.Cmp(t, err, scold.ErrByteSizeBadSyntax)
+	})
+
+	t.Run("negative size is forbidden", func(t *testing.T) {
+		var size scold.ByteSize
+		err := size.UnmarshalText([]byte("-1MB"))
+
+		td.Cmp(t, err, scold.ErrByteSizeBadSyntax)
+	})
+
+	t.Run("jibberish is forbidden", func(t *testing.T) {
+		var size scold.ByteSize
+		err := size.UnmarshalText([]byte("lotsMB"))
+
+		td.Cmp(t, err, scold.ErrByteSizeBadSyntax)
+	})
+
+	t.Run("string representation", func(t *testing.T) {
+		td.Cmp(t, (512 * scold.Byte).String(), "512B")
+		td.Cmp(t, (1536 * scold.Kilobyte).String(), "1.5MB")
+		td.Cmp(t, (256 * scold.Megabyte).String(), "256.0MB")
+	})
+}
+
+func TestTimeLimitMode(t *testing.T) {
+	t.Run("wall", func(t *testing.T) {
+		mode := scold.CPUTimeMode
+		err := mode.UnmarshalText([]byte("wall"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, mode, scold.WallTimeMode)
+	})
+
+	t.Run("cpu", func(t *testing.T) {
+		var mode scold.TimeLimitMode
+		err := mode.UnmarshalText([]byte("cpu"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, mode, scold.CPUTimeMode)
+	})
+
+	t.Run("unknown mode is forbidden", func(t *testing.T) {
+		var mode scold.TimeLimitMode
+		err := mode.UnmarshalText([]byte("user"))
+
+		td.Cmp(t, err, scold.ErrTimeLimitModeBadSyntax)
+	})
+}
+
+func TestCaseMode(t *testing.T) {
+	t.Run("sensitive", func(t *testing.T) {
+		mode := scold.CaseInsensitive
+		err := mode.UnmarshalText([]byte("sensitive"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, mode, scold.CaseSensitive)
+	})
+
+	t.Run("insensitive", func(t *testing.T) {
+		var mode scold.CaseMode
+		err := mode.UnmarshalText([]byte("insensitive"))
+
+		td.CmpNoError(t, err)
+		td.Cmp(t, mode, scold.CaseInsensitive)
+	})
+
+	t.Run("unknown mode is forbidden", func(t *testing.T) {
+		var mode scold.CaseMode
+		err := mode.UnmarshalText([]byte("upper"))
+
+		td.Cmp(t, err, scold.ErrCaseModeBadSyntax)
+	})
+}
+
+func TestOrderMode(t *testing.T) {
+	cases := []struct {
+		Text string
+		Want scold.OrderMode
+	}{
+		{"strict", scold.StrictOrder},
+		{"any_tokens", scold.AnyTokenOrder},
+		{"any_lines", scold.AnyLineOrder},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Text, func(t *testing.T) {
+			mode := scold.OrderMode(42)
+			err := mode.UnmarshalText([]byte(test.Text))
+
+			td.CmpNoError(t, err)
+			td.Cmp(t, mode, test.Want)
+			td.Cmp(t, mode.String(), test.Text)
+		})
+	}
+
+	t.Run("unknown mode is forbidden", func(t *testing.T) {
+		var mode scold.OrderMode
+		err := mode.UnmarshalText([]byte("any"))
+
+		td.Cmp(t, err, scold.ErrOrderModeBadSyntax)
+	})
+}
+
+func TestCompareMode(t *testing.T) {
+	cases := []struct {
+		Text string
+		Want scold.CompareMode
+	}{
+		{"tokens", scold.CompareTokens},
+		{"lines", scold.CompareLines},
+		{"exact", scold.CompareExact},
+	}
+
+	for _, test := range cases {
+		t.Run(test.Text, func(t *testing.T) {
+			mode := scold.CompareMode(42)
+			err := mode.UnmarshalText([]byte(test.Text))
+
+			td.CmpNoError(t, err)
+			td.Cmp(t, mode, test.Want)
+			td.Cmp(t, mode.String(), test.Text)
+		})
+	}
+
+	t.Run("unknown mode is forbidden", func(t *testing.T) {
+		var mode scold.CompareMode
+		err := mode.UnmarshalText([]byte("bytes"))
+
+		td.Cmp(t, err, scold.ErrCompareModeBadSyntax)
+	})
+}
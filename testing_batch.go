@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -23,13 +22,44 @@ const (
 	RE
 	// Time Limit
 	TL
+	// Memory Limit
+	ML
+	// Output Limit Exceeded
+	OLE
 )
 
+var verdictNames = map[Verdict]string{
+	OK:  "OK",
+	IE:  "IE",
+	WA:  "WA",
+	RE:  "RE",
+	TL:  "TL",
+	ML:  "ML",
+	OLE: "OLE",
+}
+
+// String returns the abbreviation of the verdict, like "WA".
+func (v Verdict) String() string {
+	if name, ok := verdictNames[v]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Verdict(%d)", int(v))
+}
+
 // TLError is an error that can occur during Processer execution that
 // indicates that it was prematurely killed by TestingBatch, because
 // it exceeded the time limit.
 const TLError StringError = "Time limit exceeded"
 
+// MLError is an error that can be returned by Processer to indicate that
+// the process was killed or failed, because it exceeded the memory limit.
+const MLError StringError = "Memory limit exceeded"
+
+// OLError is an error that can be returned by Processer to indicate that
+// the process was killed, because it printed more than the output limit.
+const OLError StringError = "Output limit exceeded"
+
 // TestingEventListener provides a way for users of scold to subscribe
 // to the events produced by TestingBatch and to operate in a reactive fashion.
 // The functions will stall the TestingBatch event loop, and thus could be not
@@ -124,6 +154,15 @@ type TestResult struct {
 	Verdict    Verdict
 	Time       time.Duration
 
+	// CheckerMessage is the explanation provided by the Checker, if it
+	// was used to judge the test.
+	CheckerMessage string
+
+	// AnswerIndex is the index of the answer in Test.Answers() that
+	// RichAnswer shows. If the output matches none of the answers, it's
+	// the closest one.
+	AnswerIndex int
+
 	TestExecutionResult
 }
 
@@ -132,6 +171,10 @@ type TestResult struct {
 // It utilizes an instance of Processer to run tests, and an instance of
 // Stopwatcher to track time limit. Optionally, user can set ResultPrinter
 // to a custom function to output useful statistics about test case's result.
+//
+// By default, the outputs are judged by comparing them against the answers
+// lexeme-wise. If Checker is set, it is consulted instead, although the
+// lexemes are still compared to produce RichOut and RichAnswer.
 type TestingBatch struct {
 	inputs Inputs
 
@@ -139,12 +182,12 @@ type TestingBatch struct {
 
 	Results map[int]*TestResult
 	Lx      *Lexer
+	Checker Checker
 
 	startTimes map[int]time.Time
 
-	Proc          Processer
-	procCancels   map[int]func()
-	procCancelsMu sync.Mutex
+	Proc        Processer
+	procCancels map[int]func()
 
 	ThreadPool WorkerPool
 
@@ -164,6 +207,12 @@ func NewTestingBatch(inputs Inputs, proc Processer, swatch Stopwatcher, pool Wor
 
 		Lx: &Lexer{
 			Precision: uint(inputs.Config.Prec),
+			AbsEps:    inputs.Config.AbsEps,
+			RelEps:    inputs.Config.RelEps,
+
+			IgnoreCase: inputs.Config.Case == CaseInsensitive,
+			Order:      inputs.Config.Order,
+			Mode:       inputs.Config.Compare,
 		},
 
 		startTimes: make(map[int]time.Time),
@@ -179,7 +228,12 @@ func NewTestingBatch(inputs Inputs, proc Processer, swatch Stopwatcher, pool Wor
 	}
 }
 
-func (b *TestingBatch) launchTest(id int, in string) {
+// Config returns the suite's configuration the tests are run with.
+func (b *TestingBatch) Config() InputsConfig {
+	return b.inputs.Config
+}
+
+func (b *TestingBatch) launchTest(ctx context.Context, id int, in string) {
 	defer func() {
 		if e := recover(); e != nil {
 			b.complete <- TestExecutionResult{
@@ -190,21 +244,14 @@ func (b *TestingBatch) launchTest(id int, in string) {
 		}
 	}()
 
-	ctx, cancel := context.WithCancel(context.Background())
-
-	b.procCancelsMu.Lock()
-	if _, exists := b.procCancels[id]; !exists {
-		b.procCancels[id] = cancel
-	} else {
-		b.procCancelsMu.Unlock()
-		cancel()
+	// The time is up before the worker picked up the test
+	if ctx.Err() != nil {
 		b.complete <- TestExecutionResult{
 			ID:  id,
 			Err: TLError,
 		}
 		return
 	}
-	b.procCancelsMu.Unlock()
 
 	out, err := b.Proc.Run(ctx, strings.NewReader(in))
 
@@ -219,110 +266,235 @@ func (b *TestingBatch) launchTest(id int, in string) {
 	}
 }
 
-func (b *TestingBatch) nextOldestRunning(previous int) int {
-	for id := previous + 1; id < len(b.inputs.Tests)+1; id++ {
-		_, finished := b.Results[id]
-		if !finished {
-			return id
+// launch dispatches the test to the thread pool and starts tracking its
+// time limit. Each test has its own deadline, so the tests that hang are
+// stopped regardless of the tests launched before them.
+func (b *TestingBatch) launch(id int) error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	swatch := b.Swatch
+	if config := b.inputs.Tests[id-1].Config; config != nil {
+		if overrider, ok := swatch.(TimeLimitOverrider); ok {
+			swatch = overrider.WithTimeLimit(config.Tl.Duration)
+		}
+		ctx = ContextWithTimeLimit(ctx, config.Tl.Duration)
+	}
+
+	err := b.ThreadPool.Execute(RunnableFunc(func() {
+		b.launchTest(ctx, id, b.inputs.Tests[id-1].Input)
+	}))
+
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	b.Listener.TestStarted(id)
+	b.startTimes[id] = b.Swatch.Now()
+	b.procCancels[id] = cancel
+
+	deadline := swatch.TimeLimit(b.startTimes[id])
+	go func() {
+		select {
+		case <-deadline:
+			cancel()
+		case <-ctx.Done():
 		}
+	}()
+
+	return nil
+}
+
+// Lexer returns the lexer the test's output is compared with. If the test
+// overrides the comparison of floats, it's a modified copy of Lx.
+func (b *TestingBatch) Lexer(test *Test) *Lexer {
+	if test.Config == nil {
+		return b.Lx
 	}
 
-	return len(b.inputs.Tests) + 1
+	lx := *b.Lx
+	lx.Precision = uint(test.Config.Prec)
+	lx.AbsEps = test.Config.AbsEps
+	lx.RelEps = test.Config.RelEps
+	return &lx
+}
+
+// judge assigns the verdict to the test's result. The verdicts that do
+// not depend on the output take precedence over WA.
+func (b *TestingBatch) judge(test *Test, result *TestResult) {
+	lx := b.Lexer(test)
+
+	answers := test.Answers()
+
+	answerLexemes := lx.Scan(answers[0])
+	result.RichAnswer, _ = lx.Compare(answerLexemes, nil)
+
+	if result.Err == TLError {
+		result.Verdict = TL
+		return
+	}
+
+	if result.Err == MLError {
+		result.Verdict = ML
+		return
+	}
+
+	if result.Err == OLError {
+		result.Verdict = OLE
+		return
+	}
+
+	if result.Err != nil {
+		result.Verdict = IE
+		return
+	}
+
+	// An interactive executable usually crashes after the interactor hangs
+	// up on it, so the interactor's rejection is preferred over RE.
+	interactorRejected := result.Out.Interactor != nil && result.Out.Interactor.ExitCode != 0
+	if result.Out.ExitCode != 0 && !interactorRejected {
+		result.Verdict = RE
+		return
+	}
+
+	got := lx.Scan(result.Out.Stdout)
+	ok := compareAnswers(lx, got, answers, result)
+
+	if b.Checker != nil {
+		verdict, msg, err := b.check(test, result.Out)
+		if err != nil {
+			result.Err = fmt.Errorf("checker: %w", err)
+			result.Verdict = IE
+			return
+		}
+
+		result.Verdict = verdict
+		result.CheckerMessage = msg
+		return
+	}
+
+	if ok {
+		result.Verdict = OK
+	} else {
+		result.Verdict = WA
+	}
+}
+
+// compareAnswers compares the output lexemes against each of the answers
+// until one of them matches. The rich texts of the matching answer, or of
+// the closest one if none matches, are stored in result. The closest answer
+// is the one with the fewest highlighted characters on both sides.
+func compareAnswers(lx *Lexer, got []string, answers []string, result *TestResult) (ok bool) {
+	bestDiff := -1
+	for i, answer := range answers {
+		answerLexemes := lx.Scan(answer)
+
+		richOut, okOut := lx.Compare(got, answerLexemes)
+		richAnswer, okAns := lx.Compare(answerLexemes, got)
+
+		// The verdict is determined by the positional comparison, but the
+		// aligned one points at the actual mistake.
+		if !(okOut && okAns) && lx.Order == StrictOrder {
+			richOut, richAnswer, _ = lx.CompareAligned(got, answerLexemes)
+		}
+
+		diff := highlightedCount(richOut) + highlightedCount(richAnswer)
+		if okOut && okAns {
+			diff = 0
+		}
+
+		if bestDiff == -1 || diff < bestDiff {
+			bestDiff = diff
+			result.RichOut, result.RichAnswer = richOut, richAnswer
+			result.AnswerIndex = i
+		}
+
+		if okOut && okAns {
+			return true
+		}
+	}
+
+	return false
+}
+
+func highlightedCount(rts []RichText) (count int) {
+	for _, rt := range rts {
+		for _, highlighted := range rt.Mask {
+			if highlighted {
+				count++
+			}
+		}
+	}
+
+	return
+}
+
+// check consults the checker with each of the test's answers until one of
+// them is accepted. If none is, the verdict for the first answer is
+// reported.
+func (b *TestingBatch) check(test *Test, out ExecutionResult) (Verdict, string, error) {
+	var firstVerdict Verdict
+	var firstMsg string
+
+	for i, answer := range test.Answers() {
+		verdict, msg, err := b.Checker.Check(test.Input, answer, out)
+		if err != nil {
+			return IE, "", err
+		}
+
+		if verdict == OK {
+			return verdict, msg, nil
+		}
+
+		if i == 0 {
+			firstVerdict, firstMsg = verdict, msg
+		}
+	}
+
+	return firstVerdict, firstMsg, nil
 }
 
 // Run will lauch test cases in parallel and then will wait for each test to
 // finish or for the time to timelimit. When a test is finished the verdict
 // and the time it took to execute are remembered. Additionally, ResultPrinter
-// is called on the test case's statistics. When a time limit is reached,
-// each not-yet-judged test is assigned TL verdict and the ResultPrinter is
-// also called on each test.
+// is called on the test case's statistics. When a test's time limit is
+// reached, the test is stopped and assigned TL verdict, independently of
+// the other tests that are running.
 func (b *TestingBatch) Run() {
 	nextTestID := 1
 	for ; nextTestID-1 < len(b.inputs.Tests) && nextTestID-1 < b.ThreadPool.WorkerCount(); nextTestID++ {
-		// Local variable is deliberate, since RunnableFunc below will capture
-		// variables by reference, nextTestID will be len(b.inputs.Tests)+1 when
-		// the worker picks up the job, and so cause panic
-		id := nextTestID
-		err := b.ThreadPool.Execute(RunnableFunc(func() {
-			b.launchTest(id, b.inputs.Tests[id-1].Input)
-		}))
-
+		err := b.launch(nextTestID)
 		if err != nil {
 			break
 		}
-
-		b.Listener.TestStarted(id)
-		b.startTimes[id] = b.Swatch.Now()
 	}
 
-	oldestRunningID := 1
 	for len(b.Results) != len(b.inputs.Tests) {
-		result := &TestResult{}
-
-		select {
-		case <-b.Swatch.TimeLimit(b.startTimes[oldestRunningID]):
-			b.procCancelsMu.Lock()
-
-			if cancel, exists := b.procCancels[oldestRunningID]; exists {
-				cancel()
-			} else {
-				// Notify the launchTest func not to run the thread
-				b.procCancels[oldestRunningID] = func() {}
-			}
-
-			b.procCancelsMu.Unlock()
-
-			oldestRunningID = b.nextOldestRunning(oldestRunningID)
-			continue
-		case result.TestExecutionResult = <-b.complete:
+		result := &TestResult{
+			TestExecutionResult: <-b.complete,
 		}
 
+		// Stop tracking the time limit of the finished test
+		b.procCancels[result.ID]()
+		delete(b.procCancels, result.ID)
+
 		// A worker is now free, run another test if any
 		if nextTestID-1 < len(b.inputs.Tests) {
-			id := nextTestID
-			err := b.ThreadPool.Execute(RunnableFunc(func() {
-				b.launchTest(id, b.inputs.Tests[id-1].Input)
-			}))
-
+			err := b.launch(nextTestID)
 			if err == nil {
-				b.Listener.TestStarted(id)
-				b.startTimes[id] = b.Swatch.Now()
 				nextTestID++
 			}
 		}
 
 		id := result.ID
-		test := b.inputs.Tests[id-1]
+		test := &b.inputs.Tests[id-1]
 
 		result.Time = b.Swatch.Elapsed(b.startTimes[id])
 
-		answerLexemes := b.Lx.Scan(test.Output)
-		result.RichAnswer, _ = b.Lx.Compare(answerLexemes, nil)
-
-		if result.Err == TLError {
-			result.Verdict = TL
-		} else if result.Err != nil {
-			result.Verdict = IE
-		} else if result.Out.ExitCode != 0 {
-			result.Verdict = RE
-		} else {
-			got := b.Lx.Scan(result.Out.Stdout)
-
-			var okOut, okAns bool
-			result.RichOut, okOut = b.Lx.Compare(got, answerLexemes)
-			result.RichAnswer, okAns = b.Lx.Compare(answerLexemes, got)
-
-			same := okOut && okAns
-
-			if !same {
-				result.Verdict = WA
-			} else {
-				result.Verdict = OK
-			}
-		}
+		b.judge(test, result)
 
 		b.Results[id] = result
-		b.Listener.TestFinished(&b.inputs.Tests[id-1], result)
+		b.Listener.TestFinished(test, result)
 	}
 
 	b.Listener.SuiteFinished(b)
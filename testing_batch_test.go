@@ -3,10 +3,12 @@ package scold_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -71,6 +73,44 @@ func ProcFuncAnswer(ctx context.Context, in io.Reader) (scold.ExecutionResult, e
 	}, nil
 }
 
+// SequentialStopwatcher is a stopwatcher that gives each launched test its
+// own time limit, in the order specified by TLs.
+type SequentialStopwatcher struct {
+	scold.ConfigurableStopwatcher
+	TLs []time.Duration
+
+	launched int
+}
+
+func (s *SequentialStopwatcher) TimeLimit(since time.Time) <-chan time.Time {
+	s.TL = s.TLs[s.launched]
+	s.launched++
+
+	return s.ConfigurableStopwatcher.TimeLimit(since)
+}
+
+func TestVerdictString(t *testing.T) {
+	cases := []struct {
+		Verdict scold.Verdict
+		Want    string
+	}{
+		{scold.OK, "OK"},
+		{scold.IE, "IE"},
+		{scold.WA, "WA"},
+		{scold.RE, "RE"},
+		{scold.TL, "TL"},
+		{scold.ML, "ML"},
+		{scold.OLE, "OLE"},
+		{scold.Verdict(42), "Verdict(42)"},
+	}
+
+	for _, test := range cases {
+		if got := test.Verdict.String(); got != test.Want {
+			t.Errorf("got verdict %q, want %q", got, test.Want)
+		}
+	}
+}
+
 func TestNewTestingBatch(t *testing.T) {
 	t.Run("no state altering configs", func(t *testing.T) {
 		inputs := scold.Inputs{
@@ -99,6 +139,68 @@ func TestNewTestingBatch(t *testing.T) {
 			t.Errorf("got lexer precision %d, but want 22", batch.Lx.Precision)
 		}
 	})
+
+	t.Run("case option", func(t *testing.T) {
+		inputs := scold.Inputs{
+			Tests: nil,
+			Config: scold.InputsConfig{
+				Case: scold.CaseInsensitive,
+			},
+		}
+
+		batch := scold.NewTestingBatch(inputs, nil, nil, nil)
+
+		if !batch.Lx.IgnoreCase {
+			t.Errorf("got lexer that respects the case, but want the one that ignores it")
+		}
+	})
+
+	t.Run("order option", func(t *testing.T) {
+		inputs := scold.Inputs{
+			Tests: nil,
+			Config: scold.InputsConfig{
+				Order: scold.AnyTokenOrder,
+			},
+		}
+
+		batch := scold.NewTestingBatch(inputs, nil, nil, nil)
+
+		if batch.Lx.Order != scold.AnyTokenOrder {
+			t.Errorf("got lexer order %v, but want %v", batch.Lx.Order, scold.AnyTokenOrder)
+		}
+	})
+
+	t.Run("compare option", func(t *testing.T) {
+		inputs := scold.Inputs{
+			Tests: nil,
+			Config: scold.InputsConfig{
+				Compare: scold.CompareLines,
+			},
+		}
+
+		batch := scold.NewTestingBatch(inputs, nil, nil, nil)
+
+		if batch.Lx.Mode != scold.CompareLines {
+			t.Errorf("got lexer mode %v, but want %v", batch.Lx.Mode, scold.CompareLines)
+		}
+	})
+
+	t.Run("tolerance options", func(t *testing.T) {
+		inputs := scold.Inputs{
+			Tests: nil,
+			Config: scold.InputsConfig{
+				AbsEps: 1e-6,
+				RelEps: 1e-9,
+			},
+		}
+
+		batch := scold.NewTestingBatch(inputs, nil, nil, nil)
+
+		if batch.Lx.AbsEps != 1e-6 || batch.Lx.RelEps != 1e-9 {
+			t.Errorf("got lexer tolerance %g absolute, %g relative, but want 1e-06 and 1e-09",
+				batch.Lx.AbsEps, batch.Lx.RelEps)
+		}
+	})
 }
 
 // IDEA: Add support for presentation errors...
@@ -229,6 +331,52 @@ func TestTestingBatch(t *testing.T) {
 		scold.AssertListenerNotified(t, listener, inputs.Tests)
 	})
 
+	t.Run("tests may override the precision",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{
+						Input:  "1\n",
+						Output: "1.2\n",
+						Config: &scold.InputsConfig{Prec: 1},
+					},
+					{
+						Input:  "2\n",
+						Output: "2.3\n",
+					},
+				},
+				Config: scold.InputsConfig{
+					Prec: 2,
+				},
+			}
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(ProcFuncBogusFloatingPoint),
+			}
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(2)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Listener = listener
+			batch.Run()
+
+			// The first test passes only with the precision it overrides.
+			want := map[int]scold.Verdict{
+				1: scold.OK,
+				2: scold.WA,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, want)
+			scold.AssertListenerNotified(t, listener, inputs.Tests)
+
+			if batch.Lx.Precision != 2 {
+				t.Errorf("got suite lexer precision %d, but want 2", batch.Lx.Precision)
+			}
+		})
+
 	t.Run("all WA",
 		func(t *testing.T) {
 			inputs := scold.Inputs{
@@ -348,11 +496,386 @@ func TestTestingBatch(t *testing.T) {
 			}
 		})
 
+	t.Run("checker judges the outputs instead of lexer",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{
+						Input:  "3\n",
+						Output: "1 2 3\n",
+					},
+					{
+						Input:  "2\n",
+						Output: "2 1\n",
+					},
+					{
+						Input:  "4\n",
+						Output: "1 2 3 4\n",
+					},
+				},
+			}
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(ProcFuncIntegerSequence),
+			}
+
+			// Accepts any output that has the same number of lexemes
+			checker := scold.CheckerFunc(func(input, answer string, out scold.ExecutionResult) (scold.Verdict, string, error) {
+				if len(strings.Fields(answer)) != len(strings.Fields(out.Stdout)) {
+					return scold.WA, "wrong length", nil
+				}
+
+				if input == "4\n" {
+					return scold.OK, "", errors.New("oops")
+				}
+
+				return scold.OK, "ok", nil
+			})
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(2)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Checker = checker
+			batch.Listener = listener
+			batch.Run()
+
+			want := map[int]scold.Verdict{
+				1: scold.OK,
+				2: scold.OK,
+				3: scold.IE,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, want)
+			scold.AssertCallCount(t, "proc.Run()", proc.CallCount(), 3)
+			scold.AssertListenerNotified(t, listener, inputs.Tests)
+
+			if batch.Results[2].CheckerMessage != "ok" {
+				t.Errorf("got checker message %q, want %q", batch.Results[2].CheckerMessage, "ok")
+			}
+
+			if !batch.Results[2].RichOut[0].Colorful() {
+				t.Errorf("got output without highlighting, want it to be compared against the answer anyway")
+			}
+		})
+
+	t.Run("output may match any of the answers",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{
+						Input:      "3\n",
+						Output:     "3 2 1\n",
+						AltOutputs: []string{"1 2 3\n"},
+					},
+					{
+						Input:      "2\n",
+						Output:     "2 1\n",
+						AltOutputs: []string{"1 2 3\n", "5 6 7\n"},
+					},
+				},
+			}
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(ProcFuncIntegerSequence),
+			}
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(2)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Listener = listener
+			batch.Run()
+
+			want := map[int]scold.Verdict{
+				1: scold.OK,
+				2: scold.WA,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, want)
+			scold.AssertListenerNotified(t, listener, inputs.Tests)
+
+			if batch.Results[1].AnswerIndex != 1 {
+				t.Errorf("got matching answer #%d, want #1", batch.Results[1].AnswerIndex)
+			}
+
+			if batch.Results[2].AnswerIndex != 1 {
+				t.Errorf("got closest answer #%d, want #1", batch.Results[2].AnswerIndex)
+			}
+
+			if batch.Results[2].RichAnswer[2].Str != "3" || !batch.Results[2].RichAnswer[2].Colorful() {
+				t.Errorf("got wrong rich answer, %s", litter.Sdump(batch.Results[2].RichAnswer))
+			}
+		})
+
+	t.Run("wrong answer report aligns the output with the answer",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{
+						Input:  "2\n",
+						Output: "0 1 2\n",
+					},
+				},
+			}
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(ProcFuncIntegerSequence),
+			}
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(1)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Listener = listener
+			batch.Run()
+
+			scold.AssertVerdicts(t, batch.Results, map[int]scold.Verdict{1: scold.WA})
+
+			for _, rt := range batch.Results[1].RichOut {
+				if rt.Colorful() {
+					t.Errorf("got output highlighted, want only the missing lexeme of the answer, %s",
+						litter.Sdump(batch.Results[1].RichOut))
+					break
+				}
+			}
+
+			richAnswer := batch.Results[1].RichAnswer
+			if !richAnswer[0].Colorful() || richAnswer[1].Colorful() || richAnswer[2].Colorful() {
+				t.Errorf("got wrong rich answer, %s", litter.Sdump(richAnswer))
+			}
+		})
+
+	t.Run("checker is consulted with each of the answers",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{
+						Input:      "3\n",
+						Output:     "3 2 1\n",
+						AltOutputs: []string{"1 2 3 \n"},
+					},
+					{
+						Input:      "2\n",
+						Output:     "2 1\n",
+						AltOutputs: []string{"1 3\n"},
+					},
+				},
+			}
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(ProcFuncIntegerSequence),
+			}
+
+			checkCount := 0
+			checker := scold.CheckerFunc(func(input, answer string, out scold.ExecutionResult) (scold.Verdict, string, error) {
+				checkCount++
+
+				if answer != out.Stdout {
+					return scold.WA, "expected " + strings.TrimSpace(answer), nil
+				}
+
+				return scold.OK, "ok", nil
+			})
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(1)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Checker = checker
+			batch.Listener = listener
+			batch.Run()
+
+			want := map[int]scold.Verdict{
+				1: scold.OK,
+				2: scold.WA,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, want)
+			scold.AssertCallCount(t, "checker.Check()", checkCount, 4)
+
+			if batch.Results[2].CheckerMessage != "expected 2 1" {
+				t.Errorf("got checker message %q, want %q", batch.Results[2].CheckerMessage, "expected 2 1")
+			}
+		})
+
+	t.Run("interactor's rejection is preferred over runtime error",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{Input: "1\n"},
+					{Input: "2\n"},
+					{Input: "3\n"},
+				},
+			}
+
+			// The executable crashes on tests 1 and 2, but only on test 2
+			// the interactor hangs up on it. On test 3 it wins.
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(
+					func(ctx context.Context, r io.Reader) (scold.ExecutionResult, error) {
+						var num int
+						fmt.Fscan(r, &num)
+
+						out := scold.ExecutionResult{
+							ExitCode:   1,
+							Interactor: &scold.ExecutionResult{},
+							Transcript: "> 42\n",
+						}
+
+						if num == 2 {
+							out.Interactor.ExitCode = 1
+						} else if num == 3 {
+							out.ExitCode = 0
+						}
+
+						return out, nil
+					}),
+			}
+
+			checker := scold.CheckerFunc(func(input, answer string, out scold.ExecutionResult) (scold.Verdict, string, error) {
+				if out.Interactor.ExitCode != 0 {
+					return scold.WA, "", nil
+				}
+
+				return scold.OK, "", nil
+			})
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(3)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Checker = checker
+			batch.Listener = listener
+			batch.Run()
+
+			want := map[int]scold.Verdict{
+				1: scold.RE,
+				2: scold.WA,
+				3: scold.OK,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, want)
+			scold.AssertListenerNotified(t, listener, inputs.Tests)
+		})
+
+	t.Run("memory limit exceeded",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{
+						Input:  "1\n",
+						Output: "1\n",
+					},
+					{
+						Input:  "2\n",
+						Output: "2\n",
+					},
+				},
+			}
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(
+					func(ctx context.Context, r io.Reader) (scold.ExecutionResult, error) {
+						var num int
+						fmt.Fscan(r, &num)
+
+						out := scold.ExecutionResult{
+							Stdout:     fmt.Sprintln(num),
+							PeakMemory: 10 * scold.Megabyte,
+						}
+
+						if num == 2 {
+							out.PeakMemory = 300 * scold.Megabyte
+							return out, scold.MLError
+						}
+
+						return out, nil
+					}),
+			}
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(2)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Listener = listener
+			batch.Run()
+
+			want := map[int]scold.Verdict{
+				1: scold.OK,
+				2: scold.ML,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, want)
+			scold.AssertListenerNotified(t, listener, inputs.Tests)
+
+			if got := batch.Results[2].Out.PeakMemory; got != 300*scold.Megabyte {
+				t.Errorf("got peak memory %v, want %v", got, 300*scold.Megabyte)
+			}
+		})
+
+	t.Run("output limit exceeded",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{Input: "1\n", Output: "1\n"},
+					{Input: "2\n", Output: "2\n"},
+				},
+			}
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(
+					func(ctx context.Context, r io.Reader) (scold.ExecutionResult, error) {
+						var num int
+						fmt.Fscan(r, &num)
+
+						if num == 2 {
+							return scold.ExecutionResult{
+								ExitCode: -1,
+								Stdout:   strings.Repeat("2\n", 1000),
+							}, scold.OLError
+						}
+
+						return scold.ExecutionResult{
+							Stdout: fmt.Sprintln(num),
+						}, nil
+					}),
+			}
+
+			swatch := &scold.ConfigurableStopwatcher{Clock: clockwork.NewFakeClock()}
+			pool := scold.NewSpyThreadPool(2)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Listener = listener
+			batch.Run()
+
+			want := map[int]scold.Verdict{
+				1: scold.OK,
+				2: scold.OLE,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, want)
+			scold.AssertListenerNotified(t, listener, inputs.Tests)
+		})
+
 	t.Run("single TL (proc doesn't run because it didn't have time to dispatch)",
 		func(t *testing.T) {
 			inputs := scold.Inputs{
 				Tests: []scold.Test{
-					{"\n", "bar\n"},
+					{Input: "\n", Output: "bar\n"},
 				},
 			}
 
@@ -419,7 +942,7 @@ func TestTestingBatch(t *testing.T) {
 		func(t *testing.T) {
 			inputs := scold.Inputs{
 				Tests: []scold.Test{
-					{"\n", "bar\n"},
+					{Input: "\n", Output: "bar\n"},
 				},
 			}
 
@@ -490,12 +1013,82 @@ func TestTestingBatch(t *testing.T) {
 			scold.AssertTimes(t, batch.Results, timesWant)
 		})
 
+	t.Run("tests may override the time limit",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{
+						Input:  "\n",
+						Output: "bar\n",
+						Config: &scold.InputsConfig{Tl: scold.NewPositiveDuration(10 * time.Second)},
+					},
+				},
+			}
+
+			clock := clockwork.NewFakeClock()
+
+			var tlSeen time.Duration
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(
+					func(ctx context.Context, r io.Reader) (scold.ExecutionResult, error) {
+						tlSeen, _ = scold.TimeLimitFromContext(ctx)
+
+						select {
+						case <-clock.After(5 * time.Second):
+						case <-ctx.Done():
+							return scold.ExecutionResult{}, scold.TLError
+						}
+
+						return scold.ExecutionResult{Stdout: "bar\n"}, nil
+					}),
+			}
+
+			swatch := &scold.ConfigurableStopwatcher{
+				Clock: clock,
+				TL:    3 * time.Second,
+			}
+			pool := scold.NewSpyThreadPool(1)
+			listener := &scold.SpyPrinter{}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Listener = listener
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				batch.Run()
+				wg.Done()
+			}()
+
+			clock.BlockUntil(2)
+			clock.Advance(5 * time.Second)
+
+			wg.Wait()
+
+			testsWant := map[int]scold.Verdict{
+				1: scold.OK,
+			}
+
+			timesWant := map[int]time.Duration{
+				1: 5 * time.Second,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, testsWant)
+			scold.AssertListenerNotified(t, listener, inputs.Tests)
+			scold.AssertTimes(t, batch.Results, timesWant)
+
+			if tlSeen != 10*time.Second {
+				t.Errorf("processer got time limit %v, want %v", tlSeen, 10*time.Second)
+			}
+		})
+
 	t.Run("two TL, thread count 1",
 		func(t *testing.T) {
 			inputs := scold.Inputs{
 				Tests: []scold.Test{
-					{"\n", "bar\n"},
-					{"\n", "bar\n"},
+					{Input: "\n", Output: "bar\n"},
+					{Input: "\n", Output: "bar\n"},
 				},
 			}
 
@@ -574,8 +1167,8 @@ func TestTestingBatch(t *testing.T) {
 		func(t *testing.T) {
 			inputs := scold.Inputs{
 				Tests: []scold.Test{
-					{"\n", "bar\n"},
-					{"\n", "bar\n"},
+					{Input: "\n", Output: "bar\n"},
+					{Input: "\n", Output: "bar\n"},
 				},
 			}
 
@@ -626,7 +1219,7 @@ func TestTestingBatch(t *testing.T) {
 				wg.Done()
 			}()
 
-			clock.BlockUntil(3)
+			clock.BlockUntil(4)
 			clock.Advance(3 * time.Second)
 
 			wg.Wait()
@@ -655,10 +1248,10 @@ func TestTestingBatch(t *testing.T) {
 		func(t *testing.T) {
 			inputs := scold.Inputs{
 				Tests: []scold.Test{
-					{"2\n", "2\n"},
-					{"5\n", "5\n"},
-					{"2\n", "2\n"},
-					{"5\n", "5\n"},
+					{Input: "2\n", Output: "2\n"},
+					{Input: "5\n", Output: "5\n"},
+					{Input: "2\n", Output: "2\n"},
+					{Input: "5\n", Output: "5\n"},
 				},
 			}
 
@@ -727,11 +1320,11 @@ func TestTestingBatch(t *testing.T) {
 			// test 2: ---
 			// test 3:   --
 			// test 4:    ---
-			clock.BlockUntil(3)
+			clock.BlockUntil(4)
 			clock.Advance(2 * time.Second)
 
 			advances := []time.Duration{time.Second, time.Second, 2 * time.Second}
-			blocks := []int{4, 5, 5}
+			blocks := []int{5, 5, 4}
 			for i := range advances {
 				<-doneCh
 				clock.BlockUntil(blocks[i])
@@ -767,4 +1360,92 @@ func TestTestingBatch(t *testing.T) {
 			scold.AssertCallCount(t, "process cancel", killCount, 2)
 			scold.AssertTimes(t, batch.Results, timesWant)
 		})
+
+	t.Run("later test times out before the older one finishes",
+		func(t *testing.T) {
+			inputs := scold.Inputs{
+				Tests: []scold.Test{
+					{Input: "4\n", Output: "4\n"},
+					{Input: "10\n", Output: "10\n"},
+				},
+			}
+
+			clock := clockwork.NewFakeClock()
+
+			proc := &scold.SpyProcesser{
+				Proc: scold.ProcesserFunc(
+					func(ctx context.Context, r io.Reader) (scold.ExecutionResult, error) {
+						var num int
+						fmt.Fscan(r, &num)
+
+						select {
+						case <-clock.After(time.Duration(num) * time.Second):
+						case <-ctx.Done():
+							return scold.ExecutionResult{}, scold.TLError
+						}
+
+						return scold.ExecutionResult{
+							Stdout: fmt.Sprintln(num),
+						}, nil
+					}),
+			}
+
+			// Test 2 is launched together with test 1, but has a tighter
+			// time limit.
+			swatch := &SequentialStopwatcher{
+				ConfigurableStopwatcher: scold.ConfigurableStopwatcher{Clock: clock},
+				TLs:                     []time.Duration{5 * time.Second, 2 * time.Second},
+			}
+			pool := scold.NewSpyThreadPool(2)
+
+			var finishedIDs []int
+			doneCh := make(chan struct{}, 1)
+			done := func(test *scold.Test, result *scold.TestResult) {
+				finishedIDs = append(finishedIDs, result.ID)
+				doneCh <- struct{}{}
+			}
+
+			batch := scold.NewTestingBatch(inputs, proc, swatch, pool)
+			batch.Listener = scold.TestFinishedCallback(done)
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				batch.Run()
+				wg.Done()
+			}()
+
+			// Time:   12345
+			// test 1: ----|
+			// test 2: --|
+			clock.BlockUntil(4)
+			clock.Advance(2 * time.Second)
+
+			<-doneCh
+			clock.BlockUntil(3)
+			clock.Advance(2 * time.Second)
+
+			<-doneCh
+			wg.Wait()
+
+			testsWant := map[int]scold.Verdict{
+				1: scold.OK,
+				2: scold.TL,
+			}
+
+			timesWant := map[int]time.Duration{
+				1: 4 * time.Second,
+				2: 2 * time.Second,
+			}
+
+			scold.AssertResultIDInvariant(t, batch)
+			scold.AssertVerdicts(t, batch.Results, testsWant)
+			scold.AssertThreadCount(t, pool, 2)
+			scold.AssertCallCount(t, "proc.Run()", proc.CallCount(), 2)
+			scold.AssertTimes(t, batch.Results, timesWant)
+
+			if len(finishedIDs) != 2 || finishedIDs[0] != 2 {
+				t.Errorf("got tests finished in order %v, want test 2 to finish first", finishedIDs)
+			}
+		})
 }
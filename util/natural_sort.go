@@ -0,0 +1,63 @@
+package util
+
+// NaturalLess compares the strings in the natural order, i.e., the runs of
+// digits are compared as numbers, so that "2.in" comes before "10.in". The
+// rest is compared byte-wise. If the numbers are equal, but written with
+// a different number of leading zeros, the shorter one goes first.
+func NaturalLess(a, b string) bool {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if !isDigit(a[i]) || !isDigit(b[j]) {
+			if a[i] != b[j] {
+				return a[i] < b[j]
+			}
+
+			i++
+			j++
+			continue
+		}
+
+		numA, nextI := digitRun(a, i)
+		numB, nextJ := digitRun(b, j)
+
+		trimmedA, trimmedB := trimZeros(numA), trimZeros(numB)
+		if len(trimmedA) != len(trimmedB) {
+			return len(trimmedA) < len(trimmedB)
+		}
+
+		if trimmedA != trimmedB {
+			return trimmedA < trimmedB
+		}
+
+		if len(numA) != len(numB) {
+			return len(numA) < len(numB)
+		}
+
+		i, j = nextI, nextJ
+	}
+
+	return len(a)-i < len(b)-j
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// digitRun returns the run of digits starting at the index and the index
+// right after it.
+func digitRun(s string, start int) (string, int) {
+	end := start
+	for end < len(s) && isDigit(s[end]) {
+		end++
+	}
+
+	return s[start:end], end
+}
+
+func trimZeros(num string) string {
+	for len(num) > 1 && num[0] == '0' {
+		num = num[1:]
+	}
+
+	return num
+}
@@ -0,0 +1,55 @@
+package util_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/kuredoro/scold/util"
+)
+
+func TestNaturalLess(t *testing.T) {
+	cases := []struct {
+		A, B string
+		Want bool
+	}{
+		{"", "", false},
+		{"", "a", true},
+		{"a", "", false},
+		{"a", "b", true},
+		{"2", "10", true},
+		{"10", "2", false},
+		{"2.in", "10.in", true},
+		{"test9", "test10", true},
+		{"test10", "test10", false},
+		{"02", "2", false},
+		{"2", "02", true},
+		{"01", "2", true},
+		{"1.a", "1.b", true},
+		{"1", "1.a", true},
+		{"a10b2", "a10b10", true},
+		{"99999999999999999999999", "100000000000000000000000", true},
+	}
+
+	for _, test := range cases {
+		t.Run(fmt.Sprintf("%q<%q", test.A, test.B), func(t *testing.T) {
+			got := util.NaturalLess(test.A, test.B)
+			if got != test.Want {
+				t.Errorf("got %v, want %v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestNaturalLessSorting(t *testing.T) {
+	names := []string{"10.in", "1.in", "2.in", "README", "02.in", "100.in", "9.in"}
+	want := []string{"1.in", "2.in", "02.in", "9.in", "10.in", "100.in", "README"}
+
+	sort.Slice(names, func(i, j int) bool {
+		return util.NaturalLess(names[i], names[j])
+	})
+
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+}